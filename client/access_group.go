@@ -0,0 +1,128 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// AccessItem is a source or destination of an access group. It covers every
+// item of its type when AllCovered is set, and the items in Children
+// otherwise. A destination with a Parent network or host covers services of
+// that parent instead.
+type AccessItem struct {
+	Type       string   `json:"type"`
+	AllCovered bool     `json:"allCovered"`
+	Parent     string   `json:"parent,omitempty"`
+	Children   []string `json:"children"`
+}
+
+type AccessGroup struct {
+	Id          string       `json:"id,omitempty"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Source      []AccessItem `json:"source"`
+	Destination []AccessItem `json:"destination"`
+}
+
+const (
+	AccessItemTypeUserGroup = "USER_GROUP"
+	AccessItemTypeNetwork   = "NETWORK"
+	AccessItemTypeHost      = "HOST"
+)
+
+func (c *Client) GetAccessGroups(ctx context.Context) ([]AccessGroup, error) {
+	var accessGroups []AccessGroup
+	err := c.getAll(ctx, c.apiPath("access-groups"), &accessGroups)
+	if err != nil {
+		return nil, err
+	}
+	return accessGroups, nil
+}
+
+func (c *Client) GetAccessGroupByName(ctx context.Context, name string) (*AccessGroup, error) {
+	var accessGroups []AccessGroup
+	err := c.getFiltered(ctx, "access-groups", url.Values{"name": {name}}, &accessGroups)
+	if err != nil {
+		return nil, err
+	}
+	for _, ag := range accessGroups {
+		if ag.Name == name {
+			return &ag, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) GetAccessGroupById(ctx context.Context, accessGroupId string) (*AccessGroup, error) {
+	var accessGroup AccessGroup
+	found, err := c.getById(ctx, "access-groups", accessGroupId, &accessGroup, func() (bool, error) {
+		accessGroups, err := c.GetAccessGroups(ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, ag := range accessGroups {
+			if ag.Id == accessGroupId {
+				accessGroup = ag
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil || !found {
+		return nil, err
+	}
+	return &accessGroup, nil
+}
+
+func (c *Client) CreateAccessGroup(ctx context.Context, accessGroup AccessGroup) (*AccessGroup, error) {
+	accessGroupJson, err := json.Marshal(accessGroup)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL("access-groups"), bytes.NewBuffer(accessGroupJson))
+	if err != nil {
+		return nil, err
+	}
+	var ag *AccessGroup
+	err = c.createIdempotently(ctx, func() error {
+		body, err := c.doMutation(req, "access-groups")
+		if err != nil {
+			return err
+		}
+		ag = &AccessGroup{}
+		return json.Unmarshal(body, ag)
+	}, func() (bool, error) {
+		existing, err := c.GetAccessGroupByName(ctx, accessGroup.Name)
+		ag = existing
+		return existing != nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ag, nil
+}
+
+func (c *Client) UpdateAccessGroup(ctx context.Context, accessGroup AccessGroup) error {
+	accessGroupJson, err := json.Marshal(accessGroup)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.apiURL("access-groups/%s", accessGroup.Id), bytes.NewBuffer(accessGroupJson))
+	if err != nil {
+		return err
+	}
+	_, err = c.doMutation(req, "access-groups")
+	return err
+}
+
+func (c *Client) DeleteAccessGroup(ctx context.Context, accessGroupId string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.apiURL("access-groups/%s", accessGroupId), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.doMutation(req, "access-groups")
+	return err
+}
@@ -0,0 +1,73 @@
+package client
+
+import "context"
+
+// OpenVPNCloudAPI is the set of operations the provider performs against the
+// OpenVPN Cloud API. It is implemented by Client, and by the in-memory fake in
+// the client/fake package for tests.
+type OpenVPNCloudAPI interface {
+	GetAccessGroupByName(ctx context.Context, name string) (*AccessGroup, error)
+	GetAccessGroupById(ctx context.Context, accessGroupId string) (*AccessGroup, error)
+	CreateAccessGroup(ctx context.Context, accessGroup AccessGroup) (*AccessGroup, error)
+	UpdateAccessGroup(ctx context.Context, accessGroup AccessGroup) error
+	DeleteAccessGroup(ctx context.Context, accessGroupId string) error
+
+	GetConnectors(ctx context.Context) ([]Connector, error)
+	GetConnectorByName(ctx context.Context, name string) (*Connector, error)
+	GetConnectorById(ctx context.Context, connectorId string) (*Connector, error)
+	GetConnectorsForNetwork(ctx context.Context, networkId string) ([]Connector, error)
+	AddConnector(ctx context.Context, connector Connector, networkItemId string) (*Connector, error)
+	DeleteConnector(ctx context.Context, connectorId string, networkItemId string, networkItemType string) error
+
+	CreateDevice(ctx context.Context, userId string, device Device) (*Device, error)
+	GetDevice(ctx context.Context, userId string, deviceId string) (*Device, error)
+	UpdateDevice(ctx context.Context, userId string, device Device) error
+	DeleteDevice(ctx context.Context, userId string, deviceId string) error
+
+	CreateDnsRecord(ctx context.Context, record DnsRecord) (*DnsRecord, error)
+	GetDnsRecord(ctx context.Context, recordId string) (*DnsRecord, error)
+	UpdateDnsRecord(ctx context.Context, record DnsRecord) error
+	DeleteDnsRecord(ctx context.Context, recordId string) error
+
+	GetHosts(ctx context.Context) ([]Host, error)
+	GetHostByName(ctx context.Context, name string) (*Host, error)
+	GetHostById(ctx context.Context, hostId string) (*Host, error)
+	CreateHost(ctx context.Context, host Host) (*Host, error)
+	UpdateHost(ctx context.Context, host Host) error
+	DeleteHost(ctx context.Context, hostId string) error
+
+	GetLocationContextById(ctx context.Context, locationContextId string) (*LocationContext, error)
+	CreateLocationContext(ctx context.Context, locationContext LocationContext) (*LocationContext, error)
+	UpdateLocationContext(ctx context.Context, locationContext LocationContext) error
+	DeleteLocationContext(ctx context.Context, locationContextId string) error
+
+	GetNetworks(ctx context.Context) ([]Network, error)
+	GetNetworkByName(ctx context.Context, name string) (*Network, error)
+	GetNetworkById(ctx context.Context, networkId string) (*Network, error)
+	CreateNetwork(ctx context.Context, network Network) (*Network, error)
+	UpdateNetwork(ctx context.Context, network Network) error
+	DeleteNetwork(ctx context.Context, networkId string) error
+
+	CreateRoute(ctx context.Context, networkId string, route Route) (*Route, error)
+	DeleteRoute(ctx context.Context, networkId string, routeId string) error
+	GetRoutes(ctx context.Context, networkId string) ([]Route, error)
+	GetNetworkRoute(ctx context.Context, networkId string, routeId string) (*Route, error)
+	GetRouteById(ctx context.Context, routeId string) (*Route, error)
+	UpdateRoute(ctx context.Context, networkId string, route Route) error
+
+	CreateUser(ctx context.Context, user User) (*User, error)
+	GetUser(ctx context.Context, username string, role string) (*User, error)
+	GetUserById(ctx context.Context, userId string) (*User, error)
+	UpdateUser(ctx context.Context, user User) error
+	DeleteUser(ctx context.Context, userId string) error
+
+	GetUserGroup(ctx context.Context, name string) (*UserGroup, error)
+	GetUserGroupById(ctx context.Context, userGroupId string) (*UserGroup, error)
+	CreateUserGroup(ctx context.Context, userGroup UserGroup) (*UserGroup, error)
+	UpdateUserGroup(ctx context.Context, userGroup UserGroup) error
+	DeleteUserGroup(ctx context.Context, userGroupId string) error
+
+	GetVpnRegion(ctx context.Context, regionId string) (*VpnRegion, error)
+}
+
+var _ OpenVPNCloudAPI = (*Client)(nil)
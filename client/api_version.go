@@ -0,0 +1,128 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	APIVersionBeta = "beta"
+	APIVersionV1   = "v1"
+
+	DefaultAPIVersion = APIVersionBeta
+)
+
+// WithAPIVersion selects the version of the OpenVPN Cloud API to talk to.
+func WithAPIVersion(version string) Option {
+	return func(c *Client) error {
+		if version != APIVersionBeta && version != APIVersionV1 {
+			return fmt.Errorf("unsupported API version %q: must be %q or %q", version, APIVersionBeta, APIVersionV1)
+		}
+		c.APIVersion = version
+		return nil
+	}
+}
+
+// apiPath returns the path of an endpoint under the root of the configured
+// API version.
+func (c *Client) apiPath(format string, args ...interface{}) string {
+	return fmt.Sprintf("/api/%s/%s", c.APIVersion, fmt.Sprintf(format, args...))
+}
+
+// apiURL returns the absolute URL of an endpoint under the root of the
+// configured API version.
+func (c *Client) apiURL(format string, args ...interface{}) string {
+	return c.BaseURL + c.apiPath(format, args...)
+}
+
+// The v1 API manages connectors under the network or host they belong to,
+// rather than through a single collection filtered by network item.
+var v1ConnectorCollections = map[string]string{
+	NetworkItemTypeNetwork: "networks/connectors",
+	NetworkItemTypeHost:    "hosts/connectors",
+}
+
+var v1ConnectorParameters = map[string]string{
+	NetworkItemTypeNetwork: "networkId",
+	NetworkItemTypeHost:    "hostId",
+}
+
+func (c *Client) connectorCreateURL(networkItemId string, networkItemType string) string {
+	if c.APIVersion == APIVersionV1 {
+		return c.apiURL("%s?%s=%s", v1ConnectorCollections[networkItemType], v1ConnectorParameters[networkItemType], networkItemId)
+	}
+	return c.apiURL("connectors?networkItemId=%s&networkItemType=%s", networkItemId, networkItemType)
+}
+
+func (c *Client) connectorURL(connectorId string, networkItemId string, networkItemType string) string {
+	if c.APIVersion == APIVersionV1 {
+		return c.apiURL("%s/%s", v1ConnectorCollections[networkItemType], connectorId)
+	}
+	return c.apiURL("connectors/%s?networkItemId=%s&networkItemType=%s", connectorId, networkItemId, networkItemType)
+}
+
+// v1Route is the route representation of the v1 API, which stores the value
+// of every route type in subnet.
+type v1Route struct {
+	Id            string `json:"id,omitempty"`
+	Type          string `json:"type"`
+	Subnet        string `json:"subnet"`
+	NetworkItemId string `json:"networkItemId,omitempty"`
+}
+
+// v1Network is the network representation of the v1 API, which requires a
+// tunneling protocol.
+type v1Network struct {
+	Network
+	Routes            []v1Route `json:"routes"`
+	TunnelingProtocol string    `json:"tunnelingProtocol"`
+}
+
+// v1User is the user representation of the v1 API, which does not accept
+// devices when a user is created.
+type v1User struct {
+	User
+	Devices []Device `json:"devices,omitempty"`
+}
+
+const v1DefaultTunnelingProtocol = "OPENVPN"
+
+func (c *Client) marshalRoute(route Route) ([]byte, error) {
+	if c.APIVersion == APIVersionV1 {
+		return json.Marshal(v1Route{Id: route.Id, Type: route.Type, Subnet: route.Value})
+	}
+	return json.Marshal(route)
+}
+
+func (c *Client) marshalNetwork(network Network) ([]byte, error) {
+	if c.APIVersion == APIVersionV1 {
+		routes := make([]v1Route, 0, len(network.Routes))
+		for _, r := range network.Routes {
+			routes = append(routes, v1Route{Id: r.Id, Type: r.Type, Subnet: r.Value})
+		}
+		return json.Marshal(v1Network{Network: network, Routes: routes, TunnelingProtocol: v1DefaultTunnelingProtocol})
+	}
+	return json.Marshal(network)
+}
+
+func (c *Client) marshalUser(user User) ([]byte, error) {
+	if c.APIVersion == APIVersionV1 {
+		return json.Marshal(v1User{User: user})
+	}
+	return json.Marshal(user)
+}
+
+// normalizeRoute maps a route read from the API to the beta representation,
+// where domain routes carry their value in Domain instead of Subnet.
+func (c *Client) normalizeRoute(route *Route) {
+	if c.APIVersion == APIVersionV1 && route.Type == RouteTypeDomain && route.Domain == "" {
+		route.Domain = route.Subnet
+		route.Subnet = ""
+	}
+}
+
+func (c *Client) normalizeNetwork(network *Network) {
+	for i := range network.Routes {
+		c.normalizeRoute(&network.Routes[i])
+	}
+}
@@ -0,0 +1,276 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	AuditOutcomeSucceeded = "succeeded"
+	AuditOutcomeFailed    = "failed"
+	// AuditOutcomeUnknown is recorded when the request failed in a way that
+	// leaves it unknown whether the API applied the change.
+	AuditOutcomeUnknown = "unknown"
+)
+
+// auditCollections are the path segments that name a collection of objects
+// rather than the id of an object.
+var auditCollections = map[string]bool{
+	"access-groups":     true,
+	"networks":          true,
+	"routes":            true,
+	"connectors":        true,
+	"hosts":             true,
+	"dns-records":       true,
+	"users":             true,
+	"user-groups":       true,
+	"devices":           true,
+	"location-contexts": true,
+}
+
+var auditOperations = map[string]string{
+	http.MethodPost:   "create",
+	http.MethodPut:    "update",
+	http.MethodPatch:  "update",
+	http.MethodDelete: "delete",
+}
+
+// AuditEntry is a line of the audit log, written for every request that
+// changes an object, whether or not it succeeded. Updates and deletes also
+// record the object as it was before the request, and updates that succeeded
+// the object as it is after it, along with the top-level fields that changed.
+type AuditEntry struct {
+	Timestamp  time.Time       `json:"timestamp"`
+	Operation  string          `json:"operation"`
+	ObjectType string          `json:"object_type"`
+	ObjectId   string          `json:"object_id,omitempty"`
+	Method     string          `json:"method"`
+	Path       string          `json:"path"`
+	RequestId  string          `json:"request_id,omitempty"`
+	Request    json.RawMessage `json:"request,omitempty"`
+	Response   json.RawMessage `json:"response,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	Changed    []string        `json:"changed,omitempty"`
+	Outcome    string          `json:"outcome"`
+	StatusCode int             `json:"status_code,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// auditLog appends entries to a JSON lines file. The file is opened for each
+// entry so that several provider processes can share it.
+type auditLog struct {
+	mutex sync.Mutex
+	path  string
+}
+
+// WithAuditLog appends an entry to the file at path for every create, update
+// and delete request sent to the API. The file is created if it does not
+// exist. The object an update or delete applies to is read before the request
+// is sent, so that the entry can record it.
+func WithAuditLog(path string) Option {
+	return func(c *Client) error {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("unable to open audit log: %w", err)
+		}
+		c.audit = &auditLog{path: path}
+		return f.Close()
+	}
+}
+
+func (al *auditLog) write(entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	al.mutex.Lock()
+	defer al.mutex.Unlock()
+	f, err := os.OpenFile(al.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// auditMutation records the outcome of a request that changes an object,
+// which was before as read by auditPrior. A failure to write the audit log is
+// only logged, since the change itself has already been made.
+func (c *Client) auditMutation(ctx context.Context, req *http.Request, requestBody []byte, before json.RawMessage, responseBody []byte, err error) {
+	if c.audit == nil {
+		return
+	}
+	objectType, objectId := auditObject(strings.TrimPrefix(req.URL.Path, c.apiPath("")))
+	entry := AuditEntry{
+		Timestamp:  time.Now().UTC(),
+		Operation:  auditOperations[req.Method],
+		ObjectType: objectType,
+		ObjectId:   objectId,
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		RequestId:  req.Header.Get(RequestIdHeader),
+		Request:    c.auditPayload(requestBody),
+		Before:     before,
+		Outcome:    AuditOutcomeSucceeded,
+	}
+	if err != nil {
+		entry.Outcome = AuditOutcomeFailed
+		if isAmbiguous(err) {
+			entry.Outcome = AuditOutcomeUnknown
+		}
+		entry.Error = c.redact(err.Error())
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			entry.StatusCode = apiErr.StatusCode
+		}
+	} else {
+		entry.Response = c.auditPayload(responseBody)
+		if entry.Operation == "update" {
+			entry.After = entry.Response
+			if entry.After == nil {
+				entry.After = entry.Request
+			}
+			entry.Changed = changedFields(entry.Before, entry.After)
+		}
+		if entry.ObjectId == "" {
+			var created struct {
+				Id string `json:"id"`
+			}
+			if json.Unmarshal(responseBody, &created) == nil {
+				entry.ObjectId = created.Id
+			}
+		}
+	}
+	if err := c.audit.write(entry); err != nil {
+		tflog.Warn(ctx, "Unable to write the audit log", "path", c.audit.path, "error", err.Error())
+	}
+}
+
+// auditPrior reads the object at objectPath, which a request is about to update or
+// delete, for the audit log. It tries the endpoint of the object, then the
+// list of its collection, and then the field named after the collection in the
+// parent object, which is where the API returns the devices of a user. It
+// returns nil when the object cannot be read.
+func (c *Client) auditPrior(ctx context.Context, objectPath string) json.RawMessage {
+	body, err := c.auditGet(ctx, objectPath)
+	if err == nil {
+		return c.auditPayload(body)
+	}
+	if !isUnsupportedLookup(err) {
+		return nil
+	}
+	collection, id := path.Split(strings.TrimSuffix(objectPath, "/"))
+	collection = strings.TrimSuffix(collection, "/")
+	var items []json.RawMessage
+	body, err = c.fetchAll(ctx, collection)
+	if isUnsupportedLookup(err) {
+		parentPath, field := path.Split(collection)
+		body, err = c.auditGet(ctx, strings.TrimSuffix(parentPath, "/"))
+		if err != nil {
+			return nil
+		}
+		var parent map[string]json.RawMessage
+		if json.Unmarshal(body, &parent) != nil {
+			return nil
+		}
+		body = parent[field]
+	} else if err != nil {
+		return nil
+	}
+	if json.Unmarshal(body, &items) != nil {
+		return nil
+	}
+	for _, item := range items {
+		var object struct {
+			Id string `json:"id"`
+		}
+		if json.Unmarshal(item, &object) == nil && object.Id == id {
+			return c.auditPayload(item)
+		}
+	}
+	return nil
+}
+
+func (c *Client) auditGet(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.DoRequest(req)
+}
+
+// changedFields returns the sorted top-level fields of after whose value is
+// not the same in before.
+func changedFields(before json.RawMessage, after json.RawMessage) []string {
+	var beforeFields, afterFields map[string]interface{}
+	if json.Unmarshal(before, &beforeFields) != nil || json.Unmarshal(after, &afterFields) != nil {
+		return nil
+	}
+	changed := []string{}
+	for field, value := range afterFields {
+		if !reflect.DeepEqual(beforeFields[field], value) {
+			changed = append(changed, field)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// auditObject returns the type of the object a request path points to, which
+// is the last collection in the path, and its id if the path has one.
+func auditObject(path string) (string, string) {
+	objectType, objectId := "", ""
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		if auditCollections[segment] || (objectType != "" && objectId != "") || objectType == "" {
+			objectType, objectId = segment, ""
+			continue
+		}
+		objectId = segment
+	}
+	return objectType, objectId
+}
+
+// auditPayload returns the body with its secrets removed, if it is JSON.
+func (c *Client) auditPayload(body []byte) json.RawMessage {
+	redactedBody := []byte(c.redact(string(body)))
+	if len(body) == 0 || !json.Valid(redactedBody) {
+		return nil
+	}
+	return redactedBody
+}
+
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil
+	}
+	return b
+}
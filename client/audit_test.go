@@ -0,0 +1,147 @@
+package client_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client/fakeapi"
+)
+
+// TestAuditBeforeAndAfter checks that updates and deletes record the object
+// as it was before the request, whichever way the API lets it be read, and
+// that updates record it as it is after the request.
+func TestAuditBeforeAndAfter(t *testing.T) {
+	for name, tc := range map[string]struct {
+		mutate  func(ctx context.Context, c *client.Client, backend client.OpenVPNCloudAPI) (string, error)
+		before  map[string]interface{}
+		after   map[string]interface{}
+		changed []string
+	}{
+		"update from the object endpoint": {
+			mutate: func(ctx context.Context, c *client.Client, backend client.OpenVPNCloudAPI) (string, error) {
+				record, err := backend.CreateDnsRecord(ctx, client.DnsRecord{Domain: "audit.example.com", IPV4Addresses: []string{"10.0.0.1"}})
+				if err != nil {
+					return "", err
+				}
+				return record.Id, c.UpdateDnsRecord(ctx, client.DnsRecord{Id: record.Id, Domain: "audit.example.com", IPV4Addresses: []string{"10.0.0.2"}})
+			},
+			before:  map[string]interface{}{"domain": "audit.example.com", "ipv4Addresses": []interface{}{"10.0.0.1"}},
+			after:   map[string]interface{}{"domain": "audit.example.com", "ipv4Addresses": []interface{}{"10.0.0.2"}},
+			changed: []string{"ipv4Addresses"},
+		},
+		"delete from the object endpoint": {
+			mutate: func(ctx context.Context, c *client.Client, backend client.OpenVPNCloudAPI) (string, error) {
+				network, err := backend.CreateNetwork(ctx, client.Network{Name: "tf-audit-network", InternetAccess: client.InternetAccessLocal})
+				if err != nil {
+					return "", err
+				}
+				return network.Id, c.DeleteNetwork(ctx, network.Id)
+			},
+			before: map[string]interface{}{"name": "tf-audit-network"},
+		},
+		"update from the collection": {
+			mutate: func(ctx context.Context, c *client.Client, backend client.OpenVPNCloudAPI) (string, error) {
+				network, err := backend.CreateNetwork(ctx, client.Network{Name: "tf-audit-route", InternetAccess: client.InternetAccessLocal})
+				if err != nil {
+					return "", err
+				}
+				route, err := backend.CreateRoute(ctx, network.Id, client.Route{Type: client.RouteTypeIPV4, Value: "10.60.0.0/24"})
+				if err != nil {
+					return "", err
+				}
+				return route.Id, c.UpdateRoute(ctx, network.Id, client.Route{Id: route.Id, Type: client.RouteTypeIPV4, Value: "10.61.0.0/24"})
+			},
+			before:  map[string]interface{}{"subnet": "10.60.0.0/24"},
+			after:   map[string]interface{}{"subnet": "10.61.0.0/24"},
+			changed: []string{"subnet"},
+		},
+		"delete from the parent object": {
+			mutate: func(ctx context.Context, c *client.Client, backend client.OpenVPNCloudAPI) (string, error) {
+				user, err := backend.CreateUser(ctx, client.User{Username: "tf-audit-user", Email: "tf-audit-user@example.com", Role: client.UserRoleMember})
+				if err != nil {
+					return "", err
+				}
+				device, err := backend.CreateDevice(ctx, user.Id, client.Device{Name: "tf-audit-device"})
+				if err != nil {
+					return "", err
+				}
+				return device.Id, c.DeleteDevice(ctx, user.Id, device.Id)
+			},
+			before: map[string]interface{}{"name": "tf-audit-device"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			api := fakeapi.NewServer()
+			defer api.Close()
+			auditLogPath := filepath.Join(t.TempDir(), "audit.jsonl")
+			c, err := client.NewClient(ctx, api.URL, api.ClientId, api.ClientSecret, client.WithRequestsPerSecond(0), client.WithAuditLog(auditLogPath))
+			if err != nil {
+				t.Fatal(err)
+			}
+			objectId, err := tc.mutate(ctx, c, api.Backend)
+			if err != nil {
+				t.Fatal(err)
+			}
+			entry := lastAuditEntry(t, auditLogPath)
+			if entry.ObjectId != objectId || entry.Outcome != client.AuditOutcomeSucceeded {
+				t.Fatalf("unexpected audit entry %+v", entry)
+			}
+			checkAuditPayload(t, "before", entry.Before, objectId, tc.before)
+			checkAuditPayload(t, "after", entry.After, objectId, tc.after)
+			if !reflect.DeepEqual(entry.Changed, tc.changed) {
+				t.Errorf("expected the changed fields %v, got %v", tc.changed, entry.Changed)
+			}
+		})
+	}
+}
+
+func lastAuditEntry(t *testing.T, auditLogPath string) client.AuditEntry {
+	t.Helper()
+	f, err := os.Open(auditLogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var last client.AuditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if err := json.Unmarshal(scanner.Bytes(), &last); err != nil {
+			t.Fatalf("invalid audit log entry %q: %v", scanner.Text(), err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return last
+}
+
+// checkAuditPayload checks that the payload is the object with the given id
+// and has the expected fields, or is missing if no fields are expected.
+func checkAuditPayload(t *testing.T, name string, payload json.RawMessage, objectId string, expected map[string]interface{}) {
+	t.Helper()
+	if expected == nil {
+		if payload != nil {
+			t.Errorf("expected no %s payload, got %s", name, payload)
+		}
+		return
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(payload, &object); err != nil {
+		t.Fatalf("invalid %s payload %q: %v", name, payload, err)
+	}
+	if id, ok := object["id"]; ok && id != objectId {
+		t.Errorf("expected the %s payload to be the object %s, got %s", name, objectId, payload)
+	}
+	for field, value := range expected {
+		if !reflect.DeepEqual(object[field], value) {
+			t.Errorf("expected the %s payload to have %s %v, got %s", name, field, value, payload)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultCacheTTL = 30 * time.Second
+
+// listCache keeps a short-lived snapshot of every list endpoint read through
+// getAll, and makes concurrent reads of the same endpoint share a single
+// request. Mutations invalidate the snapshots of the collections they touch.
+type listCache struct {
+	calls      sharedCalls
+	mutex      sync.Mutex
+	ttl        time.Duration
+	generation uint64
+	snapshots  map[string]snapshot
+}
+
+type snapshot struct {
+	items   []byte
+	expires time.Time
+}
+
+func newListCache(ttl time.Duration) *listCache {
+	return &listCache{
+		ttl:       ttl,
+		snapshots: map[string]snapshot{},
+	}
+}
+
+// WithCacheTTL sets how long a snapshot of a list endpoint is reused before it
+// is read again from the API. A TTL of 0 disables snapshots, but concurrent
+// reads of the same endpoint still share a single request.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(c *Client) error {
+		if ttl < 0 {
+			return fmt.Errorf("invalid cache TTL %s: must not be negative", ttl)
+		}
+		c.cache.ttl = ttl
+		return nil
+	}
+}
+
+// load returns the JSON array of every item of the list endpoint at path,
+// from its snapshot if there is a fresh one, or from fetch otherwise.
+// Concurrent reads of the same endpoint share a single fetch, see sharedCalls.
+func (lc *listCache) load(ctx context.Context, path string, fetch func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	lc.mutex.Lock()
+	s, ok := lc.snapshots[path]
+	generation := lc.generation
+	lc.mutex.Unlock()
+	if ok && time.Now().Before(s.expires) {
+		return s.items, nil
+	}
+	// Reads that start after an invalidation must not join a request that
+	// started before it, so the generation is part of the key.
+	key := fmt.Sprintf("%d:%s", generation, path)
+	items, err := lc.calls.do(ctx, key, func(ctx context.Context) (interface{}, error) {
+		items, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		lc.put(path, generation, items)
+		return items, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items.([]byte), nil
+}
+
+// sharedCalls makes concurrent calls with the same key share a single run of
+// their function. Callers stop waiting for it when their own ctx is done, but
+// the run itself is only cancelled once every caller waiting for it has given
+// up, so that the first caller giving up does not fail the others.
+type sharedCalls struct {
+	mutex sync.Mutex
+	calls map[string]*sharedCall
+}
+
+type sharedCall struct {
+	done    chan struct{}
+	val     interface{}
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// do returns the result of fn, run once for the concurrent callers of key
+// with a context that keeps the values of the ctx of the first caller.
+func (sc *sharedCalls) do(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	sc.mutex.Lock()
+	if sc.calls == nil {
+		sc.calls = map[string]*sharedCall{}
+	}
+	call, ok := sc.calls[key]
+	if !ok {
+		runCtx, cancel := context.WithCancel(detachedContext{ctx})
+		call = &sharedCall{done: make(chan struct{}), cancel: cancel}
+		sc.calls[key] = call
+		go func() {
+			defer close(call.done)
+			defer cancel()
+			call.val, call.err = fn(runCtx)
+			sc.mutex.Lock()
+			defer sc.mutex.Unlock()
+			if sc.calls[key] == call {
+				delete(sc.calls, key)
+			}
+		}()
+	}
+	call.waiters++
+	sc.mutex.Unlock()
+	select {
+	case <-call.done:
+		return call.val, call.err
+	case <-ctx.Done():
+		sc.mutex.Lock()
+		defer sc.mutex.Unlock()
+		call.waiters--
+		if call.waiters == 0 {
+			// A later caller must not join a run that is being cancelled
+			if sc.calls[key] == call {
+				delete(sc.calls, key)
+			}
+			call.cancel()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// detachedContext carries the values of its parent, such as the logger, but
+// never expires. It is the parent of the contexts of shared runs, which are
+// cancelled on their own.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (dc detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (dc detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (dc detachedContext) Err() error {
+	return nil
+}
+
+func (dc detachedContext) Value(key interface{}) interface{} {
+	return dc.parent.Value(key)
+}
+
+func (lc *listCache) put(path string, generation uint64, items []byte) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+	if lc.ttl == 0 || generation != lc.generation {
+		return
+	}
+	lc.snapshots[path] = snapshot{
+		items:   items,
+		expires: time.Now().Add(lc.ttl),
+	}
+}
+
+// invalidate drops the snapshots of every endpoint under the given paths,
+// including the filtered lists.
+func (lc *listCache) invalidate(paths ...string) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+	lc.generation++
+	for key := range lc.snapshots {
+		for _, p := range paths {
+			if key == p || strings.HasPrefix(key, p+"/") || strings.HasPrefix(key, p+"?") {
+				delete(lc.snapshots, key)
+				break
+			}
+		}
+	}
+}
+
+// doMutation performs a request that changes the given collections, records
+// it in the audit log, and invalidates their snapshots whether or not the
+// request succeeded.
+func (c *Client) doMutation(req *http.Request, collections ...string) ([]byte, error) {
+	var before json.RawMessage
+	if c.audit != nil && req.Method != http.MethodPost {
+		before = c.auditPrior(req.Context(), req.URL.Path)
+	}
+	body, err := c.DoRequest(req)
+	c.auditMutation(req.Context(), req, requestBody(req), before, body, err)
+	paths := make([]string, len(collections))
+	for i, collection := range collections {
+		paths[i] = c.apiPath(collection)
+	}
+	c.cache.invalidate(paths...)
+	return body, err
+}
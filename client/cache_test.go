@@ -0,0 +1,246 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client/fakeapi"
+)
+
+// listCounter counts the requests for the list of networks, and holds the
+// first one until it is released.
+type listCounter struct {
+	requests int32
+	hold     chan struct{}
+	held     chan struct{}
+}
+
+func newListCounter(hold bool) *listCounter {
+	lc := &listCounter{}
+	if hold {
+		lc.hold = make(chan struct{})
+		lc.held = make(chan struct{})
+	}
+	return lc
+}
+
+func (lc *listCounter) count() int32 {
+	return atomic.LoadInt32(&lc.requests)
+}
+
+func newListCountingClient(t *testing.T, api *fakeapi.Server, counter *listCounter, opts ...client.Option) *client.Client {
+	server := newInterceptedServer(t, api, func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/beta/networks" {
+			return false
+		}
+		if atomic.AddInt32(&counter.requests, 1) == 1 && counter.hold != nil {
+			close(counter.held)
+			<-counter.hold
+		}
+		return false
+	})
+	opts = append([]client.Option{client.WithRequestsPerSecond(0)}, opts...)
+	c, err := client.NewClient(context.Background(), server.URL, api.ClientId, api.ClientSecret, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCacheTTL(t *testing.T) {
+	for name, tc := range map[string]struct {
+		ttl      time.Duration
+		wait     time.Duration
+		requests int32
+	}{
+		"fresh":    {ttl: time.Minute, requests: 1},
+		"expired":  {ttl: 50 * time.Millisecond, wait: 100 * time.Millisecond, requests: 2},
+		"disabled": {ttl: 0, requests: 2},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			api := fakeapi.NewServer()
+			defer api.Close()
+			counter := newListCounter(false)
+			c := newListCountingClient(t, api, counter, client.WithCacheTTL(tc.ttl))
+			if _, err := c.GetNetworks(ctx); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(tc.wait)
+			if _, err := c.GetNetworks(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if counter.count() != tc.requests {
+				t.Errorf("expected %d list requests, got %d", tc.requests, counter.count())
+			}
+		})
+	}
+}
+
+// TestCacheInvalidatedByMutations checks that a mutation drops the snapshot of
+// its collection, whether or not it succeeded.
+func TestCacheInvalidatedByMutations(t *testing.T) {
+	for name, mutate := range map[string]func(ctx context.Context, c *client.Client) error{
+		"create": func(ctx context.Context, c *client.Client) error {
+			_, err := c.CreateNetwork(ctx, client.Network{Name: "tf-cache-create", InternetAccess: client.InternetAccessLocal})
+			return err
+		},
+		"failed update": func(ctx context.Context, c *client.Client) error {
+			err := c.UpdateNetwork(ctx, client.Network{Id: "missing", Name: "tf-cache-update", InternetAccess: client.InternetAccessLocal})
+			if !client.IsNotFound(err) {
+				return err
+			}
+			return nil
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			api := fakeapi.NewServer()
+			defer api.Close()
+			counter := newListCounter(false)
+			c := newListCountingClient(t, api, counter, client.WithCacheTTL(time.Minute))
+			if _, err := c.GetNetworks(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if err := mutate(ctx, c); err != nil {
+				t.Fatal(err)
+			}
+			networks, err := c.GetNetworks(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if counter.count() != 2 {
+				t.Errorf("expected 2 list requests, got %d", counter.count())
+			}
+			expected, _ := api.Backend.GetNetworks(ctx)
+			if len(networks) != len(expected) {
+				t.Errorf("expected %d networks, got %d", len(expected), len(networks))
+			}
+		})
+	}
+}
+
+// TestCacheGeneration checks that a read started after a mutation does not
+// join a read started before it, and that the older read does not replace the
+// snapshot of the newer one when it completes last.
+func TestCacheGeneration(t *testing.T) {
+	ctx := context.Background()
+	api := fakeapi.NewServer()
+	defer api.Close()
+	counter := newListCounter(true)
+	c := newListCountingClient(t, api, counter, client.WithCacheTTL(time.Minute))
+	stale := make(chan error, 1)
+	go func() {
+		_, err := c.GetNetworks(ctx)
+		stale <- err
+	}()
+	<-counter.held
+	network, err := c.CreateNetwork(ctx, client.Network{Name: "tf-cache-generation", InternetAccess: client.InternetAccessLocal})
+	if err != nil {
+		t.Fatal(err)
+	}
+	networks, err := c.GetNetworks(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(networks) != 1 || networks[0].Id != network.Id {
+		t.Errorf("expected the new network to be listed, got %+v", networks)
+	}
+	close(counter.hold)
+	if err := <-stale; err != nil {
+		t.Fatal(err)
+	}
+	networks, err = c.GetNetworks(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(networks) != 1 {
+		t.Errorf("expected the new network to still be listed, got %+v", networks)
+	}
+	if counter.count() != 2 {
+		t.Errorf("expected 2 list requests, got %d", counter.count())
+	}
+}
+
+// TestCacheSharedRead checks that concurrent reads of the same list share a
+// single request, and that a caller giving up does not fail the others.
+func TestCacheSharedRead(t *testing.T) {
+	api := fakeapi.NewServer()
+	defer api.Close()
+	counter := newListCounter(true)
+	c := newListCountingClient(t, api, counter, client.WithCacheTTL(0))
+	first, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error, 1)
+	go func() {
+		_, err := c.GetNetworks(first)
+		cancelled <- err
+	}()
+	<-counter.held
+	const readers = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, readers)
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.GetNetworks(context.Background())
+			errs <- err
+		}()
+	}
+	// Give the readers the time to join the request that is being held
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancelled read to fail with context.Canceled, got %v", err)
+	}
+	close(counter.hold)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if counter.count() != 1 {
+		t.Errorf("expected a single list request, got %d", counter.count())
+	}
+}
+
+// TestCacheAbandonedRead checks that a read that every caller gave up on is
+// cancelled, rather than reading the remaining pages in the background.
+func TestCacheAbandonedRead(t *testing.T) {
+	api := fakeapi.NewServer()
+	defer api.Close()
+	for i := 0; i < 3; i++ {
+		_, err := api.Backend.CreateNetwork(context.Background(), client.Network{Name: fmt.Sprintf("tf-cache-abandoned-%d", i), InternetAccess: client.InternetAccessLocal})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	counter := newListCounter(true)
+	c := newListCountingClient(t, api, counter, client.WithCacheTTL(time.Minute), client.WithPageSize(1))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error, 1)
+	go func() {
+		_, err := c.GetNetworks(ctx)
+		cancelled <- err
+	}()
+	<-counter.held
+	cancel()
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the read to fail with context.Canceled, got %v", err)
+	}
+	close(counter.hold)
+	// Give an abandoned read the time to request the next pages
+	time.Sleep(100 * time.Millisecond)
+	if counter.count() != 1 {
+		t.Errorf("expected the read to stop after its first page, got %d page requests", counter.count())
+	}
+}
@@ -0,0 +1,275 @@
+// Package cassette records the HTTP interactions of a client with the
+// OpenVPN Cloud API, or with the fake of it in package fakeapi, into a file,
+// and replays them later so that tests can run offline. Only the cassettes
+// whose Source is SourceAPI hold payloads of the real API.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Mode selects whether a cassette records new interactions or replays the
+// ones it holds.
+type Mode int
+
+const (
+	// Replay answers every request from the cassette and never reaches the
+	// network.
+	Replay Mode = iota
+	// Record sends every request to the API and saves the interactions to the
+	// cassette when it is stopped.
+	Record
+)
+
+// The values of Cassette.Source.
+const (
+	// SourceAPI is a cassette recorded against a tenant of the real API.
+	SourceAPI = "api"
+	// SourceFakeAPI is a cassette recorded against the fake API of package
+	// fakeapi. It only holds the payloads the fake API was written to return.
+	SourceFakeAPI = "fakeapi"
+)
+
+// ModeEnvVar is the environment variable that switches the tests to Record
+// when it is set to "record".
+const ModeEnvVar = "OPENVPN_CLOUD_CASSETTE_MODE"
+
+const scrubbed = "***"
+
+// droppedHeaders are never written to a cassette, either because they hold
+// credentials or because they change with every recording.
+var droppedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Date", "Content-Length"}
+
+var credentialsPattern = regexp.MustCompile(`("(?:access_token|refresh_token|id_token|client_secret)"\s*:\s*")[^"]*(")`)
+
+// ModeFromEnv returns Record when ModeEnvVar is set to "record", and Replay
+// otherwise.
+func ModeFromEnv() Mode {
+	if os.Getenv(ModeEnvVar) == "record" {
+		return Record
+	}
+	return Replay
+}
+
+// Interaction is a request sent to the API and the response it got.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request holds the parts of a request that identify it during replay.
+type Request struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is the response replayed for a request.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Cassette is an http.RoundTripper that records or replays interactions.
+//
+// During replay, a request is answered by the first interaction recorded for
+// the same method, path, query and body that has not been replayed yet. Once
+// they have all been replayed, a GET is answered again by the last of them,
+// since how often a list is read depends on timing. Any other request is
+// unexpected and fails.
+type Cassette struct {
+	// Source is what the interactions were recorded against, SourceAPI or
+	// SourceFakeAPI. It must be set before a recording is stopped.
+	Source       string        `json:"source"`
+	Interactions []Interaction `json:"interactions"`
+
+	path       string
+	mode       Mode
+	secrets    []string
+	next       http.RoundTripper
+	mutex      sync.Mutex
+	replayed   []bool
+	unexpected []string
+}
+
+// Load opens the cassette at path. In Replay mode the file must exist. In
+// Record mode it is overwritten when the cassette is stopped. Every secret is
+// replaced with a placeholder before it is written.
+func Load(path string, mode Mode, secrets ...string) (*Cassette, error) {
+	c := &Cassette{
+		path: path,
+		mode: mode,
+	}
+	for _, secret := range secrets {
+		if secret != "" {
+			c.secrets = append(c.secrets, secret)
+		}
+	}
+	if mode == Record {
+		return c, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read cassette: %w", err)
+	}
+	err = json.Unmarshal(data, c)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+	c.replayed = make([]bool, len(c.Interactions))
+	return c, nil
+}
+
+// Mode returns the mode the cassette was loaded in.
+func (c *Cassette) Mode() Mode {
+	return c.mode
+}
+
+// Wrap returns the cassette as a round tripper that sends the requests it
+// records through next, or through http.DefaultTransport if next is nil.
+func (c *Cassette) Wrap(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.next = next
+	return c
+}
+
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	request := Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.RawQuery,
+		Body:   c.scrub(string(body)),
+	}
+	if c.mode == Record {
+		return c.record(req, request)
+	}
+	return c.replay(req, request)
+}
+
+func (c *Cassette) record(req *http.Request, request Request) (*http.Response, error) {
+	res, err := c.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	header := res.Header.Clone()
+	for _, name := range droppedHeaders {
+		header.Del(name)
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.Interactions = append(c.Interactions, Interaction{
+		Request: request,
+		Response: Response{
+			StatusCode: res.StatusCode,
+			Header:     header,
+			Body:       c.scrub(string(body)),
+		},
+	})
+	return res, nil
+}
+
+func (c *Cassette) replay(req *http.Request, request Request) (*http.Response, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	match := -1
+	for i, interaction := range c.Interactions {
+		if interaction.Request != request {
+			continue
+		}
+		if !c.replayed[i] {
+			match = i
+			break
+		}
+		if request.Method == http.MethodGet {
+			match = i
+		}
+	}
+	if match < 0 {
+		description := fmt.Sprintf("%s %s", request.Method, request.Path)
+		if request.Query != "" {
+			description += "?" + request.Query
+		}
+		c.unexpected = append(c.unexpected, description)
+		return nil, fmt.Errorf("cassette %s has no interaction for %s", c.path, description)
+	}
+	c.replayed[match] = true
+	response := c.Interactions[match].Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", response.StatusCode, http.StatusText(response.StatusCode)),
+		StatusCode:    response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        response.Header.Clone(),
+		Body:          ioutil.NopCloser(strings.NewReader(response.Body)),
+		ContentLength: int64(len(response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Stop saves the recorded interactions in Record mode. In Replay mode it
+// returns an error listing the unexpected requests, if there were any.
+func (c *Cassette) Stop() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.mode == Replay {
+		if len(c.unexpected) > 0 {
+			return fmt.Errorf("cassette %s has no interaction for %s", c.path, strings.Join(c.unexpected, ", "))
+		}
+		return nil
+	}
+	if c.Source != SourceAPI && c.Source != SourceFakeAPI {
+		return fmt.Errorf("cassette %s has an invalid source %q", c.path, c.Source)
+	}
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(c)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(c.path), 0755)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.path, data.Bytes(), 0644)
+}
+
+// scrub replaces the credentials in a body with a placeholder.
+func (c *Cassette) scrub(s string) string {
+	s = credentialsPattern.ReplaceAllString(s, "${1}"+scrubbed+"${2}")
+	for _, secret := range c.secrets {
+		s = strings.ReplaceAll(s, secret, scrubbed)
+	}
+	return s
+}
@@ -0,0 +1,123 @@
+package cassette
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=s3cr3t")
+		if r.URL.Path == "/oauth/token" {
+			w.Write([]byte(`{"access_token":"t0k3n","expires_in":3600}`))
+			return
+		}
+		w.Write([]byte(`{"id":"42","owner":"client-secret"}`))
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	recorder, err := Load(path, Record, "client-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.Source = SourceFakeAPI
+	httpClient := &http.Client{Transport: recorder.Wrap(nil)}
+	token := send(t, httpClient, http.MethodPost, server.URL+"/oauth/token", `{"client_secret":"client-secret"}`, "Bearer t0k3n")
+	if token != `{"access_token":"t0k3n","expires_in":3600}` {
+		t.Errorf("the recorded client got %s", token)
+	}
+	send(t, httpClient, http.MethodGet, server.URL+"/items/42?full=true", "", "Bearer t0k3n")
+	err = recorder.Stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"t0k3n", "client-secret", "s3cr3t"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("the cassette contains %q:\n%s", secret, data)
+		}
+	}
+
+	player, err := Load(path, Replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if player.Source != SourceFakeAPI {
+		t.Errorf("expected the source of the cassette to be %q, got %q", SourceFakeAPI, player.Source)
+	}
+	httpClient = &http.Client{Transport: player.Wrap(nil)}
+	token = send(t, httpClient, http.MethodPost, "https://cassette.invalid/oauth/token", `{"client_secret":"***"}`, "Bearer ***")
+	if token != `{"access_token":"***","expires_in":3600}` {
+		t.Errorf("the replayed client got %s", token)
+	}
+	for i := 0; i < 2; i++ {
+		item := send(t, httpClient, http.MethodGet, "https://cassette.invalid/items/42?full=true", "", "Bearer ***")
+		if item != `{"id":"42","owner":"***"}` {
+			t.Errorf("the replayed client got %s", item)
+		}
+	}
+	err = player.Stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReplayUnexpectedRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	err := ioutil.WriteFile(path, []byte(`{"interactions":[{"request":{"method":"DELETE","path":"/items/42"},"response":{"status_code":204}}]}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	player, err := Load(path, Replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpClient := &http.Client{Transport: player.Wrap(nil)}
+	res, err := httpClient.Do(newRequest(t, http.MethodDelete, "https://cassette.invalid/items/42", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	for _, url := range []string{"https://cassette.invalid/items/42", "https://cassette.invalid/items/43"} {
+		_, err = httpClient.Do(newRequest(t, http.MethodDelete, url, ""))
+		if err == nil {
+			t.Errorf("expected DELETE %s to be unexpected", url)
+		}
+	}
+	err = player.Stop()
+	if err == nil || !strings.Contains(err.Error(), "DELETE /items/43") {
+		t.Errorf("expected Stop to report the unexpected requests, got %v", err)
+	}
+}
+
+func newRequest(t *testing.T, method string, url string, body string) *http.Request {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func send(t *testing.T, httpClient *http.Client, method string, url string, body string, authorization string) string {
+	req := newRequest(t, method, url, body)
+	req.Header.Set("Authorization", authorization)
+	res, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
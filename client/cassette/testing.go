@@ -0,0 +1,69 @@
+package cassette
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client/fakeapi"
+)
+
+// ReplayBaseURL is the base URL of the clients that replay a cassette. It
+// never resolves, so a request that escapes the cassette fails.
+const ReplayBaseURL = "https://cassette.invalid"
+
+// NewClient returns a client whose interactions with the API are recorded
+// into, or replayed from, testdata/cassettes/<name>.json in the directory of
+// the test. The cassette is stopped when the test ends, and the test fails if
+// a replayed client sent a request the cassette has no interaction for.
+//
+// Clients record against the tenant set in OPENVPN_CLOUD_BASE_URL,
+// OPENVPN_CLOUD_CLIENT_ID and OPENVPN_CLOUD_CLIENT_SECRET, or against an
+// in-process fake of the API when OPENVPN_CLOUD_FAKE_API is set, and the
+// cassette records which one it was in its Source. A replayed cassette that
+// was recorded against the fake API only checks the client against the fake,
+// which the test log notes.
+func NewClient(t testing.TB, name string, opts ...client.Option) *client.Client {
+	t.Helper()
+	baseURL, clientId, clientSecret := ReplayBaseURL, "client-id", "client-secret"
+	source := SourceAPI
+	mode := ModeFromEnv()
+	if mode == Record {
+		baseURL = os.Getenv("OPENVPN_CLOUD_BASE_URL")
+		clientId = os.Getenv("OPENVPN_CLOUD_CLIENT_ID")
+		clientSecret = os.Getenv("OPENVPN_CLOUD_CLIENT_SECRET")
+		if os.Getenv("OPENVPN_CLOUD_FAKE_API") != "" {
+			server := fakeapi.NewServer()
+			t.Cleanup(server.Close)
+			baseURL, clientId, clientSecret = server.URL, server.ClientId, server.ClientSecret
+			source = SourceFakeAPI
+		}
+	}
+	c, err := Load(filepath.Join("testdata", "cassettes", name+".json"), mode, clientId, clientSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode == Record {
+		c.Source = source
+	} else if c.Source != SourceAPI {
+		t.Logf("cassette %s was recorded against the fake API, not a tenant of the OpenVPN Cloud API", name)
+	}
+	t.Cleanup(func() {
+		if err := c.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	defaults := []client.Option{client.WithRoundTripper(c.Wrap)}
+	if mode == Replay {
+		// There is no API to protect from a replayed client.
+		defaults = append(defaults, client.WithRequestsPerSecond(0))
+	}
+	opts = append(defaults, opts...)
+	apiClient, err := client.NewClient(context.Background(), baseURL, clientId, clientSecret, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return apiClient
+}
@@ -2,74 +2,336 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
 )
 
+// tokenExpiryMargin is how long before the reported expiry the access token
+// is considered stale and gets refreshed.
+const tokenExpiryMargin = 60 * time.Second
+
+var apiVersionPathPattern = regexp.MustCompile(`/api/(` + APIVersionBeta + `|` + APIVersionV1 + `)$`)
+
 type Client struct {
 	HTTPClient *http.Client
 	BaseURL    string
 	Token      string
+
+	// MaxRetries is how many times a failed request is retried.
+	MaxRetries int
+	// RetryMaxWait caps the time to wait between two retries.
+	RetryMaxWait time.Duration
+	// APIVersion is the version of the API the client talks to, either
+	// APIVersionBeta or APIVersionV1.
+	APIVersion string
+	// PageSize is how many items are requested per page from paginated list
+	// endpoints.
+	PageSize int
+	// UserAgent is sent with every request when set.
+	UserAgent string
+	// ReadOnly makes the client refuse every request that could change an
+	// object.
+	ReadOnly bool
+
+	clientId     string
+	clientSecret string
+	tokenExpiry  time.Time
+	tokenMutex   sync.Mutex
+	transport    *http.Transport
+	cache        *listCache
+	routes       *routeIndex
+	limiter      *rate.Limiter
+	slots        chan struct{}
+	secretsMutex sync.Mutex
+	secrets      []string
+	audit        *auditLog
+	// unsupportedLookups holds the lookup endpoints rejected by the API.
+	unsupportedLookups sync.Map
 }
 
 type Credentials struct {
 	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// NewClient returns a client for the API at baseUrl, normalized with
+// NormalizeBaseURL, after it got an access token for the given credentials.
+func NewClient(ctx context.Context, baseUrl, clientId, clientSecret string, opts ...Option) (*Client, error) {
+	baseUrl, err := NormalizeBaseURL(baseUrl)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	c := &Client{
+		HTTPClient:   &http.Client{Timeout: DefaultRequestTimeout, Transport: transport},
+		BaseURL:      baseUrl,
+		MaxRetries:   DefaultMaxRetries,
+		RetryMaxWait: DefaultRetryMaxWait,
+		PageSize:     DefaultPageSize,
+		APIVersion:   DefaultAPIVersion,
+		clientId:     clientId,
+		clientSecret: clientSecret,
+		transport:    transport,
+		cache:        newListCache(DefaultCacheTTL),
+		routes:       newRouteIndex(),
+		limiter:      rate.NewLimiter(DefaultRequestsPerSecond, DefaultRequestsPerSecond),
+		slots:        make(chan struct{}, DefaultMaxConcurrentRequests),
+	}
+	for _, opt := range opts {
+		err := opt(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+	c.addSecret(clientSecret)
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+	err = c.refreshToken(ctx)
+	if err != nil {
+		return nil, c.redactError(err)
+	}
+	return c, nil
 }
 
-func NewClient(baseUrl, clientId, clientSecret string) (*Client, error) {
+// refreshToken requests a new access token from the OAuth endpoint. The caller
+// must hold tokenMutex.
+func (c *Client) refreshToken(ctx context.Context) error {
 	values := map[string]string{"grant_type": "client_credentials", "scope": "default"}
 	json_data, err := json.Marshal(values)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/beta/oauth/token", baseUrl), bytes.NewBuffer(json_data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL("oauth/token"), bytes.NewBuffer(json_data))
 	if err != nil {
-		return nil, err
+		return err
 	}
-	req.SetBasicAuth(clientId, clientSecret)
+	req.SetBasicAuth(c.clientId, c.clientSecret)
 	req.Header.Add("Accept", "application/json")
-	httpClient := http.DefaultClient
-	resp, err := httpClient.Do(req)
+	req.Header.Set(RequestIdHeader, newRequestId())
+	c.setUserAgent(req)
+	release, err := c.throttle(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	body, err := ioutil.ReadAll(resp.Body)
+	defer release()
+	span := startSpan(req, 0)
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return nil, err
+		c.logExchange(ctx, req, json_data, nil, nil, err, time.Since(start), 0)
+		c.endSpan(span, nil, err)
+		return err
 	}
 	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	c.logExchange(ctx, req, json_data, resp, body, err, time.Since(start), 0)
+	c.endSpan(span, resp, err)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newAPIError(req, resp.StatusCode, body)
+	}
 	var credentials Credentials
 	err = json.Unmarshal(body, &credentials)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("%w: invalid token response from %s: %v", ErrNotOpenVPNCloudAPI, req.URL.Path, err)
 	}
-	return &Client{
-		HTTPClient: &http.Client{Timeout: 10 * time.Second},
-		BaseURL:    baseUrl,
-		Token:      credentials.AccessToken,
-	}, nil
+	if credentials.AccessToken == "" {
+		return fmt.Errorf("%w: the token response from %s has no access token", ErrNotOpenVPNCloudAPI, req.URL.Path)
+	}
+	c.addSecret(credentials.AccessToken)
+	c.Token = credentials.AccessToken
+	if credentials.ExpiresIn > 0 {
+		c.tokenExpiry = time.Now().Add(time.Duration(credentials.ExpiresIn) * time.Second)
+	} else {
+		c.tokenExpiry = time.Time{}
+	}
+	return nil
 }
 
+// accessToken returns a valid access token, refreshing it first if it is
+// missing or about to expire. Concurrent callers block on tokenMutex while a
+// refresh is in flight and then share its result.
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+	if c.Token == "" || (!c.tokenExpiry.IsZero() && time.Now().Add(tokenExpiryMargin).After(c.tokenExpiry)) {
+		err := c.refreshToken(ctx)
+		if err != nil {
+			return "", err
+		}
+	}
+	return c.Token, nil
+}
+
+// invalidateToken discards the current access token if it is still the one
+// that was rejected, so that only the first caller to see a 401 forces a
+// refresh.
+func (c *Client) invalidateToken(rejected string) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+	if c.Token == rejected {
+		c.Token = ""
+	}
+}
+
+// DoRequest sends an authenticated request to the API, retrying it when that
+// is safe, and returns the body of the response. Secrets are removed from the
+// returned error. Requests that could change an object are refused with
+// ErrReadOnly when the client is read-only.
 func (c *Client) DoRequest(req *http.Request) ([]byte, error) {
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.Token))
+	if c.ReadOnly && !isSafe(req.Method) {
+		return nil, fmt.Errorf("refusing to send %s %s: %w", req.Method, req.URL.Path, ErrReadOnly)
+	}
+	body, err := c.doRequest(req)
+	return body, c.redactError(err)
+}
+
+func (c *Client) doRequest(req *http.Request) ([]byte, error) {
+	ctx := req.Context()
+	req.Header.Set(RequestIdHeader, newRequestId())
+	reauthenticated := false
+	for attempt := 0; ; attempt++ {
+		token, err := c.accessToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res, body, err := c.send(req, token, attempt)
+		if err == nil && res.StatusCode == http.StatusUnauthorized && !reauthenticated {
+			reauthenticated = true
+			c.invalidateToken(token)
+			attempt--
+			continue
+		}
+		if err == nil && res.StatusCode >= 200 && res.StatusCode < 300 {
+			return body, nil
+		}
+
+		statusCode := 0
+		var header http.Header
+		failure := err
+		if err == nil {
+			statusCode = res.StatusCode
+			header = res.Header
+			failure = newAPIError(req, statusCode, body)
+		}
+		if attempt >= c.MaxRetries || !shouldRetry(req.Method, statusCode, err) {
+			maybeProcessed := statusCode >= 500 || (err != nil && isTransientNetworkError(err) && !isDialError(err))
+			if !isIdempotent(req.Method) && maybeProcessed {
+				return nil, &ambiguousError{err: failure}
+			}
+			return nil, failure
+		}
+		wait := c.retryWait(attempt, retryAfter(header))
+		tflog.Debug(ctx, "Retrying OpenVPN Cloud API request",
+			"method", req.Method,
+			"path", req.URL.Path,
+			"request_id", req.Header.Get(RequestIdHeader),
+			"error", c.redact(failure.Error()),
+			"wait_ms", wait.Milliseconds(),
+			"attempt", attempt+1,
+			"max_retries", c.MaxRetries,
+		)
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send sends the request with the given access token and returns the response
+// with its body already read. The request body is rewound first so that the
+// same request can be sent more than once.
+func (c *Client) send(req *http.Request, token string, attempt int) (*http.Response, []byte, error) {
+	var requestBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, nil, err
+		}
+		requestBody, err = ioutil.ReadAll(body)
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(requestBody))
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	c.setUserAgent(req)
 
+	release, err := c.throttle(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+	span := startSpan(req, attempt)
+	start := time.Now()
 	res, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return nil, err
+		c.logExchange(req.Context(), req, requestBody, nil, nil, err, time.Since(start), attempt)
+		c.endSpan(span, nil, err)
+		return nil, nil, err
 	}
 	defer res.Body.Close()
 
 	body, err := ioutil.ReadAll(res.Body)
+	c.logExchange(req.Context(), req, requestBody, res, body, err, time.Since(start), attempt)
+	c.endSpan(span, res, err)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	return res, body, nil
+}
 
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		return nil, fmt.Errorf("Status code: %d, Response body: %s", res.StatusCode, string(body))
+// NormalizeBaseURL returns the base URL of the API for the URL of a tenant. It
+// defaults the scheme to https and drops the trailing slash, as well as the
+// path of an API version if the URL includes one.
+func NormalizeBaseURL(baseUrl string) (string, error) {
+	raw := strings.TrimSpace(baseUrl)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL %q: %w", baseUrl, err)
 	}
+	if (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", fmt.Errorf("invalid base URL %q: an http or https URL with a host is required", baseUrl)
+	}
+	u.Host = strings.ToLower(u.Host)
+	u.Path = apiVersionPathPattern.ReplaceAllString(strings.TrimRight(u.Path, "/"), "")
+	u.RawPath = ""
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String(), nil
+}
+
+// Ping checks that the API accepts the access token of the client. It reads
+// the VPN regions, which is cheap and is reused by later reads.
+func (c *Client) Ping(ctx context.Context) error {
+	var regions []VpnRegion
+	err := c.getAll(ctx, c.apiPath("regions"), &regions)
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return fmt.Errorf("%w: invalid response from %s: %v", ErrNotOpenVPNCloudAPI, c.apiPath("regions"), err)
+	}
+	return err
+}
 
-	return body, nil
+func (c *Client) setUserAgent(req *http.Request) {
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
 }
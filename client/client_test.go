@@ -0,0 +1,309 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client/cassette"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client/fakeapi"
+)
+
+// The TestReplay tests replay the cassettes in testdata/cassettes. The
+// checked-in cassettes were recorded against the fake API, so they only check
+// that the client reads the payloads the fake API returns, not the payloads of
+// the OpenVPN Cloud API. Set OPENVPN_CLOUD_CASSETTE_MODE=record to record them
+// again, see cassette.NewClient.
+
+// TestReplayConnectorAddresses checks that the addresses and the network item
+// of a connector are read from a replayed list of connectors.
+func TestReplayConnectorAddresses(t *testing.T) {
+	ctx := context.Background()
+	c := cassette.NewClient(t, "connector_addresses")
+	network, err := c.CreateNetwork(ctx, client.Network{
+		Name:           "tf-cassette-connector-addresses",
+		InternetAccess: "LOCAL",
+		Routes:         []client.Route{{Type: client.RouteTypeIPV4, Value: "10.10.0.0/24"}},
+		Connectors:     []client.Connector{{Name: "tf-cassette-connector", VpnRegionId: "us-east-1"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.DeleteNetwork(ctx, network.Id)
+	connectors, err := c.GetConnectorsForNetwork(ctx, network.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(connectors) != 1 {
+		t.Fatalf("expected 1 connector, got %d", len(connectors))
+	}
+	if connectors[0].IPv4Address == "" || connectors[0].IPv6Address == "" {
+		t.Errorf("expected the connector addresses to be set, got %+v", connectors[0])
+	}
+	if connectors[0].NetworkItemId != network.Id || connectors[0].NetworkItemType != client.NetworkItemTypeNetwork {
+		t.Errorf("unexpected network item of the connector: %+v", connectors[0])
+	}
+}
+
+// TestReplayRouteSubnetAndDomain checks that replayed IPv4 and domain routes
+// are read into Subnet and Domain respectively.
+func TestReplayRouteSubnetAndDomain(t *testing.T) {
+	ctx := context.Background()
+	c := cassette.NewClient(t, "route_subnet_and_domain")
+	network, err := c.CreateNetwork(ctx, client.Network{
+		Name:           "tf-cassette-route-subnet-and-domain",
+		InternetAccess: "LOCAL",
+		Routes:         []client.Route{{Type: client.RouteTypeIPV4, Value: "10.20.0.0/24"}},
+		Connectors:     []client.Connector{{Name: "tf-cassette-route-connector", VpnRegionId: "us-east-1"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.DeleteNetwork(ctx, network.Id)
+	domain, err := c.CreateRoute(ctx, network.Id, client.Route{Type: client.RouteTypeDomain, Value: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes, err := c.GetRoutes(ctx, network.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("expected 2 routes, got %d", len(routes))
+	}
+	for _, r := range routes {
+		switch r.Type {
+		case client.RouteTypeIPV4:
+			if r.Subnet != "10.20.0.0/24" || r.Domain != "" {
+				t.Errorf("unexpected IPv4 route %+v", r)
+			}
+		case client.RouteTypeDomain:
+			if r.Id != domain.Id || r.Domain != "example.com" || r.Subnet != "" {
+				t.Errorf("unexpected domain route %+v", r)
+			}
+		default:
+			t.Errorf("unexpected route type %s", r.Type)
+		}
+	}
+}
+
+// TestRouteCreatedElsewhere checks that a route created by someone else after
+// the route index and the lists of routes were read is still found by id, as
+// when it is imported.
+func TestRouteCreatedElsewhere(t *testing.T) {
+	ctx := context.Background()
+	api := fakeapi.NewServer()
+	defer api.Close()
+	c, err := client.NewClient(ctx, api.URL, api.ClientId, api.ClientSecret, client.WithRequestsPerSecond(0), client.WithCacheTTL(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	network, err := c.CreateNetwork(ctx, client.Network{Name: "tf-route-created-elsewhere", InternetAccess: client.InternetAccessLocal})
+	if err != nil {
+		t.Fatal(err)
+	}
+	route, err := c.GetRouteById(ctx, "missing")
+	if err != nil || route != nil {
+		t.Fatalf("expected no route, got %+v and %v", route, err)
+	}
+	created, err := api.Backend.CreateRoute(ctx, network.Id, client.Route{Type: client.RouteTypeIPV4, Value: "10.40.0.0/24"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	route, err = c.GetRouteById(ctx, created.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if route == nil || route.NetworkItemId != network.Id || route.Subnet != "10.40.0.0/24" {
+		t.Errorf("expected the route %s of the network %s, got %+v", created.Id, network.Id, route)
+	}
+}
+
+// TestRouteIndexAbandonedRebuild checks that a rebuild of the route index that
+// every lookup gave up on is cancelled, rather than listing the routes of the
+// remaining networks in the background.
+func TestRouteIndexAbandonedRebuild(t *testing.T) {
+	api := fakeapi.NewServer()
+	defer api.Close()
+	for i := 0; i < 10; i++ {
+		_, err := api.Backend.CreateNetwork(context.Background(), client.Network{Name: fmt.Sprintf("tf-abandoned-rebuild-%d", i), InternetAccess: client.InternetAccessLocal})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	var requests int32
+	hold := make(chan struct{})
+	server := newInterceptedServer(t, api, func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/routes") {
+			atomic.AddInt32(&requests, 1)
+			<-hold
+		}
+		return false
+	})
+	c, err := client.NewClient(context.Background(), server.URL, api.ClientId, api.ClientSecret, client.WithRequestsPerSecond(0), client.WithMaxConcurrentRequests(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error, 1)
+	go func() {
+		_, err := c.GetRouteById(ctx, "missing")
+		cancelled <- err
+	}()
+	// Wait for the first networks to have their routes listed, 8 at a time
+	for atomic.LoadInt32(&requests) < 8 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the lookup to fail with context.Canceled, got %v", err)
+	}
+	close(hold)
+	// Give an abandoned rebuild the time to list the routes of the others
+	time.Sleep(100 * time.Millisecond)
+	if count := atomic.LoadInt32(&requests); count != 8 {
+		t.Errorf("expected the rebuild to stop after the first 8 networks, got %d route list requests", count)
+	}
+}
+
+func TestReplayLookups(t *testing.T) {
+	ctx := context.Background()
+	c := cassette.NewClient(t, "lookups")
+	testLookups(ctx, t, c, "tf-cassette-lookups")
+}
+
+// TestLookupsFallBackToScans checks that every lookup still works against an
+// API that rejects the direct endpoints and the filter parameters, and that
+// each rejected endpoint is only tried once.
+func TestLookupsFallBackToScans(t *testing.T) {
+	ctx := context.Background()
+	api := fakeapi.NewServer()
+	defer api.Close()
+	var mutex sync.Mutex
+	rejected := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		filtered := r.URL.Query().Get("name") != "" || r.URL.Query().Get("username") != ""
+		direct := r.Method == http.MethodGet && len(segments) == 4 && segments[2] != "users"
+		if filtered || direct {
+			endpoint := segments[2] + "/{id}"
+			if filtered {
+				endpoint = segments[2] + "?filter"
+			}
+			mutex.Lock()
+			rejected[endpoint]++
+			mutex.Unlock()
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		api.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	c, err := client.NewClient(ctx, server.URL, api.ClientId, api.ClientSecret, client.WithRequestsPerSecond(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testLookups(ctx, t, c, "tf-fallback-lookups")
+	testLookups(ctx, t, c, "tf-fallback-lookups-again")
+	for endpoint, count := range rejected {
+		if count != 1 {
+			t.Errorf("%s was rejected %d times, expected once", endpoint, count)
+		}
+	}
+}
+
+// newInterceptedServer serves the fake API behind intercept, which can answer a
+// request itself, for instance to inject a failure, and reports whether it
+// did.
+func newInterceptedServer(t *testing.T, api *fakeapi.Server, intercept func(w http.ResponseWriter, r *http.Request) bool) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !intercept(w, r) {
+			api.ServeHTTP(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testLookups(ctx context.Context, t *testing.T, c *client.Client, name string) {
+	network, err := c.CreateNetwork(ctx, client.Network{
+		Name:           name,
+		InternetAccess: "LOCAL",
+		Routes:         []client.Route{{Type: client.RouteTypeIPV4, Value: "10.50.0.0/24"}},
+		Connectors:     []client.Connector{{Name: name + "-connector", VpnRegionId: "us-east-1"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.DeleteNetwork(ctx, network.Id)
+	host, err := c.CreateHost(ctx, client.Host{
+		Name:           name,
+		InternetAccess: "LOCAL",
+		Connectors:     []client.Connector{{Name: name + "-host-connector", VpnRegionId: "us-east-1"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.DeleteHost(ctx, host.Id)
+	user, err := c.CreateUser(ctx, client.User{Username: name, Email: name + "@example.com", Role: "MEMBER"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.DeleteUser(ctx, user.Id)
+
+	if n, err := c.GetNetworkByName(ctx, name); err != nil || n == nil || n.Id != network.Id {
+		t.Errorf("GetNetworkByName returned %+v, %v", n, err)
+	}
+	if n, err := c.GetNetworkById(ctx, network.Id); err != nil || n == nil || n.Name != name {
+		t.Errorf("GetNetworkById returned %+v, %v", n, err)
+	}
+	if n, err := c.GetNetworkById(ctx, "missing"); err != nil || n != nil {
+		t.Errorf("GetNetworkById of a missing network returned %+v, %v", n, err)
+	}
+	if h, err := c.GetHostByName(ctx, name); err != nil || h == nil || h.Id != host.Id {
+		t.Errorf("GetHostByName returned %+v, %v", h, err)
+	}
+	if h, err := c.GetHostById(ctx, host.Id); err != nil || h == nil || h.Name != name {
+		t.Errorf("GetHostById returned %+v, %v", h, err)
+	}
+	connector, err := c.GetConnectorByName(ctx, name+"-connector")
+	if err != nil || connector == nil || connector.NetworkItemId != network.Id {
+		t.Fatalf("GetConnectorByName returned %+v, %v", connector, err)
+	}
+	if conn, err := c.GetConnectorById(ctx, connector.Id); err != nil || conn == nil || conn.Name != connector.Name {
+		t.Errorf("GetConnectorById returned %+v, %v", conn, err)
+	}
+	if u, err := c.GetUser(ctx, name, "MEMBER"); err != nil || u == nil || u.Id != user.Id {
+		t.Errorf("GetUser returned %+v, %v", u, err)
+	}
+	if ug, err := c.GetUserGroup(ctx, "Default"); err != nil || ug == nil {
+		t.Errorf("GetUserGroup returned %+v, %v", ug, err)
+	}
+}
+
+func TestNormalizeBaseURL(t *testing.T) {
+	for raw, expected := range map[string]string{
+		"https://tenant.api.openvpn.com":           "https://tenant.api.openvpn.com",
+		" https://Tenant.api.openvpn.com/ ":        "https://tenant.api.openvpn.com",
+		"tenant.api.openvpn.com":                   "https://tenant.api.openvpn.com",
+		"https://tenant.api.openvpn.com/api/beta/": "https://tenant.api.openvpn.com",
+		"http://127.0.0.1:8080/api/v1":             "http://127.0.0.1:8080",
+	} {
+		if normalized, err := client.NormalizeBaseURL(raw); err != nil || normalized != expected {
+			t.Errorf("NormalizeBaseURL(%q) returned %q, %v, expected %q", raw, normalized, err, expected)
+		}
+	}
+	for _, raw := range []string{"", "ftp://tenant.api.openvpn.com", "https://"} {
+		if normalized, err := client.NormalizeBaseURL(raw); err == nil {
+			t.Errorf("NormalizeBaseURL(%q) returned %q, expected an error", raw, normalized)
+		}
+	}
+}
@@ -2,9 +2,10 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
+	"net/url"
 )
 
 type Connector struct {
@@ -22,25 +23,40 @@ const (
 	NetworkItemTypeNetwork = "NETWORK"
 )
 
-func (c *Client) GetConnectors() ([]Connector, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/beta/connectors", c.BaseURL), nil)
-	if err != nil {
-		return nil, err
-	}
-	body, err := c.DoRequest(req)
-	if err != nil {
-		return nil, err
+func (c *Client) GetConnectors(ctx context.Context) ([]Connector, error) {
+	return c.getConnectors(ctx, nil)
+}
+
+// getConnectors returns the connectors that the API returns for the given
+// filter parameters, which it may ignore.
+func (c *Client) getConnectors(ctx context.Context, filter url.Values) ([]Connector, error) {
+	if c.APIVersion == APIVersionV1 {
+		var connectors []Connector
+		for _, networkItemType := range []string{NetworkItemTypeNetwork, NetworkItemTypeHost} {
+			var itemConnectors []Connector
+			err := c.getFiltered(ctx, v1ConnectorCollections[networkItemType], filter, &itemConnectors)
+			if err != nil {
+				return nil, err
+			}
+			for _, conn := range itemConnectors {
+				if conn.NetworkItemType == "" {
+					conn.NetworkItemType = networkItemType
+				}
+				connectors = append(connectors, conn)
+			}
+		}
+		return connectors, nil
 	}
 	var connectors []Connector
-	err = json.Unmarshal(body, &connectors)
+	err := c.getFiltered(ctx, "connectors", filter, &connectors)
 	if err != nil {
 		return nil, err
 	}
 	return connectors, nil
 }
 
-func (c *Client) GetConnectorByName(name string) (*Connector, error) {
-	connectors, err := c.GetConnectors()
+func (c *Client) GetConnectorByName(ctx context.Context, name string) (*Connector, error) {
+	connectors, err := c.getConnectors(ctx, url.Values{"name": {name}})
 	if err != nil {
 		return nil, err
 	}
@@ -52,30 +68,51 @@ func (c *Client) GetConnectorByName(name string) (*Connector, error) {
 	return nil, nil
 }
 
-func (c *Client) GetConnectorById(connectorId string) (*Connector, error) {
-	connectors, err := c.GetConnectors()
-	if err != nil {
-		return nil, err
-	}
-	for _, c := range connectors {
-		if c.Id == connectorId {
-			return &c, nil
+// GetConnectorById reads the connector from the direct endpoint of each
+// collection that can hold it, which is a single one before v1.
+func (c *Client) GetConnectorById(ctx context.Context, connectorId string) (*Connector, error) {
+	if c.APIVersion == APIVersionV1 {
+		for _, networkItemType := range []string{NetworkItemTypeNetwork, NetworkItemTypeHost} {
+			connector, err := c.getConnectorById(ctx, v1ConnectorCollections[networkItemType], connectorId)
+			if err != nil {
+				return nil, err
+			}
+			if connector != nil {
+				if connector.NetworkItemType == "" {
+					connector.NetworkItemType = networkItemType
+				}
+				return connector, nil
+			}
 		}
+		return nil, nil
 	}
-	return nil, nil
+	return c.getConnectorById(ctx, "connectors", connectorId)
 }
 
-func (c *Client) GetConnectorsForNetwork(networkId string) ([]Connector, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/beta/connectors", c.BaseURL), nil)
-	if err != nil {
-		return nil, err
-	}
-	body, err := c.DoRequest(req)
-	if err != nil {
+func (c *Client) getConnectorById(ctx context.Context, collection string, connectorId string) (*Connector, error) {
+	var connector Connector
+	found, err := c.getById(ctx, collection, connectorId, &connector, func() (bool, error) {
+		var connectors []Connector
+		err := c.getAll(ctx, c.apiPath(collection), &connectors)
+		if err != nil {
+			return false, err
+		}
+		for _, conn := range connectors {
+			if conn.Id == connectorId {
+				connector = conn
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil || !found {
 		return nil, err
 	}
-	var connectors []Connector
-	err = json.Unmarshal(body, &connectors)
+	return &connector, nil
+}
+
+func (c *Client) GetConnectorsForNetwork(ctx context.Context, networkId string) ([]Connector, error) {
+	connectors, err := c.GetConnectors(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -88,29 +125,47 @@ func (c *Client) GetConnectorsForNetwork(networkId string) ([]Connector, error)
 	return networkConnectors, nil
 }
 
-func (c *Client) AddConnector(connector Connector, networkItemId string) (*Connector, error) {
+func (c *Client) AddConnector(ctx context.Context, connector Connector, networkItemId string) (*Connector, error) {
 	connectorJson, err := json.Marshal(connector)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/beta/connectors?networkItemId=%s&networkItemType=%s", c.BaseURL, networkItemId, connector.NetworkItemType), bytes.NewBuffer(connectorJson))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.connectorCreateURL(networkItemId, connector.NetworkItemType), bytes.NewBuffer(connectorJson))
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.DoRequest(req)
-	var conn Connector
-	err = json.Unmarshal(body, &conn)
+	var conn *Connector
+	err = c.createIdempotently(ctx, func() error {
+		body, err := c.doMutation(req, "connectors", "networks", "hosts")
+		if err != nil {
+			return err
+		}
+		conn = &Connector{}
+		return json.Unmarshal(body, conn)
+	}, func() (bool, error) {
+		connectors, err := c.GetConnectorsForNetwork(ctx, networkItemId)
+		if err != nil {
+			return false, err
+		}
+		for _, existing := range connectors {
+			if existing.Name == connector.Name {
+				conn = &existing
+				return true, nil
+			}
+		}
+		return false, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	return &conn, nil
+	return conn, nil
 }
 
-func (c *Client) DeleteConnector(connectorId string, networkItemId string, networkItemType string) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/beta/connectors/%s?networkItemId=%s&networkItemType=%s", c.BaseURL, connectorId, networkItemId, networkItemType), nil)
+func (c *Client) DeleteConnector(ctx context.Context, connectorId string, networkItemId string, networkItemType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.connectorURL(connectorId, networkItemId, networkItemType), nil)
 	if err != nil {
 		return err
 	}
-	_, err = c.DoRequest(req)
+	_, err = c.doMutation(req, "connectors", "networks", "hosts")
 	return err
 }
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// CreateDevice adds a device to an existing user.
+func (c *Client) CreateDevice(ctx context.Context, userId string, device Device) (*Device, error) {
+	deviceJson, err := json.Marshal(device)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL("users/%s/devices", userId), bytes.NewBuffer(deviceJson))
+	if err != nil {
+		return nil, err
+	}
+	var d *Device
+	err = c.createIdempotently(ctx, func() error {
+		body, err := c.doMutation(req, "users")
+		if err != nil {
+			return err
+		}
+		d = &Device{}
+		return json.Unmarshal(body, d)
+	}, func() (bool, error) {
+		u, err := c.GetUserById(ctx, userId)
+		if err != nil {
+			return false, err
+		}
+		for _, existing := range u.Devices {
+			if existing.Name == device.Name {
+				d = &existing
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// GetDevice returns the device of a user, or nil if the user has no such
+// device. Devices are read as part of their user.
+func (c *Client) GetDevice(ctx context.Context, userId string, deviceId string) (*Device, error) {
+	u, err := c.GetUserById(ctx, userId)
+	if err != nil || u == nil {
+		return nil, err
+	}
+	for _, d := range u.Devices {
+		if d.Id == deviceId {
+			return &d, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) UpdateDevice(ctx context.Context, userId string, device Device) error {
+	deviceJson, err := json.Marshal(device)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.apiURL("users/%s/devices/%s", userId, device.Id), bytes.NewBuffer(deviceJson))
+	if err != nil {
+		return err
+	}
+	_, err = c.doMutation(req, "users")
+	return err
+}
+
+func (c *Client) DeleteDevice(ctx context.Context, userId string, deviceId string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.apiURL("users/%s/devices/%s", userId, deviceId), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.doMutation(req, "users")
+	return err
+}
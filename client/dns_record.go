@@ -2,8 +2,8 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 )
 
@@ -14,38 +14,53 @@ type DnsRecord struct {
 	IPV6Addresses []string `json:"ipv6Addresses"`
 }
 
-func (c *Client) CreateDnsRecord(record DnsRecord) (*DnsRecord, error) {
+func (c *Client) CreateDnsRecord(ctx context.Context, record DnsRecord) (*DnsRecord, error) {
 	recordJson, err := json.Marshal(record)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/beta/dns-records", c.BaseURL), bytes.NewBuffer(recordJson))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL("dns-records"), bytes.NewBuffer(recordJson))
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.DoRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	var d DnsRecord
-	err = json.Unmarshal(body, &d)
+	var d *DnsRecord
+	err = c.createIdempotently(ctx, func() error {
+		body, err := c.doMutation(req, "dns-records")
+		if err != nil {
+			return err
+		}
+		d = &DnsRecord{}
+		return json.Unmarshal(body, d)
+	}, func() (bool, error) {
+		records, err := c.getDnsRecords(ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, existing := range records {
+			if existing.Domain == record.Domain {
+				d = &existing
+				return true, nil
+			}
+		}
+		return false, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	return &d, nil
+	return d, nil
 }
 
-func (c *Client) GetDnsRecord(recordId string) (*DnsRecord, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/beta/dns-records", c.BaseURL), nil)
-	if err != nil {
-		return nil, err
-	}
-	body, err := c.DoRequest(req)
+func (c *Client) getDnsRecords(ctx context.Context) ([]DnsRecord, error) {
+	var records []DnsRecord
+	err := c.getAll(ctx, c.apiPath("dns-records"), &records)
 	if err != nil {
 		return nil, err
 	}
-	var records []DnsRecord
-	err = json.Unmarshal(body, &records)
+	return records, nil
+}
+
+func (c *Client) GetDnsRecord(ctx context.Context, recordId string) (*DnsRecord, error) {
+	records, err := c.getDnsRecords(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -57,24 +72,24 @@ func (c *Client) GetDnsRecord(recordId string) (*DnsRecord, error) {
 	return nil, nil
 }
 
-func (c *Client) UpdateDnsRecord(record DnsRecord) error {
+func (c *Client) UpdateDnsRecord(ctx context.Context, record DnsRecord) error {
 	recordJson, err := json.Marshal(record)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/beta/dns-records/%s", c.BaseURL, record.Id), bytes.NewBuffer(recordJson))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.apiURL("dns-records/%s", record.Id), bytes.NewBuffer(recordJson))
 	if err != nil {
 		return err
 	}
-	_, err = c.DoRequest(req)
+	_, err = c.doMutation(req, "dns-records")
 	return err
 }
 
-func (c *Client) DeleteDnsRecord(recordId string) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/beta/dns-records/%s", c.BaseURL, recordId), nil)
+func (c *Client) DeleteDnsRecord(ctx context.Context, recordId string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.apiURL("dns-records/%s", recordId), nil)
 	if err != nil {
 		return err
 	}
-	_, err = c.DoRequest(req)
+	_, err = c.doMutation(req, "dns-records")
 	return err
 }
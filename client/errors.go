@@ -0,0 +1,103 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrReadOnly is returned for every request that could change an object when
+// the client is read-only.
+var ErrReadOnly = errors.New("the OpenVPN Cloud client is read-only and does not allow changes")
+
+// ErrNotOpenVPNCloudAPI is returned when the server at the base URL does not
+// answer like the OpenVPN Cloud API.
+var ErrNotOpenVPNCloudAPI = errors.New("the server does not look like the OpenVPN Cloud API")
+
+// APIError is returned for every request that the OpenVPN Cloud API answered
+// with a non-2xx status code.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Method     string
+	Path       string
+	Body       string
+}
+
+// apiErrorBody covers the error payloads returned by the API, which either
+// use errorCode/errorMessage or the error/message pair.
+type apiErrorBody struct {
+	ErrorCode    string `json:"errorCode"`
+	ErrorMessage string `json:"errorMessage"`
+	Error        string `json:"error"`
+	Message      string `json:"message"`
+}
+
+func newAPIError(req *http.Request, statusCode int, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: statusCode,
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		Body:       string(body),
+	}
+	var payload apiErrorBody
+	if json.Unmarshal(body, &payload) == nil {
+		e.Code = payload.ErrorCode
+		if e.Code == "" {
+			e.Code = payload.Error
+		}
+		e.Message = payload.ErrorMessage
+		if e.Message == "" {
+			e.Message = payload.Message
+		}
+	}
+	return e
+}
+
+func (e *APIError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = e.Body
+	}
+	if e.Code != "" {
+		return fmt.Sprintf("%s %s: status code %d (%s): %s", e.Method, e.Path, e.StatusCode, e.Code, message)
+	}
+	return fmt.Sprintf("%s %s: status code %d: %s", e.Method, e.Path, e.StatusCode, message)
+}
+
+func hasStatusCode(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// IsNotFound reports whether err is an API error for an object that does not
+// exist.
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is an API error caused by a conflicting
+// object, such as a duplicate name.
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
+
+// IsRateLimited reports whether err is an API error caused by exceeding the
+// API rate limits.
+func IsRateLimited(err error) bool {
+	return hasStatusCode(err, http.StatusTooManyRequests)
+}
+
+// IsForbidden reports whether err is an API error caused by credentials that
+// are not allowed to perform the request.
+func IsForbidden(err error) bool {
+	return hasStatusCode(err, http.StatusForbidden)
+}
+
+// IsUnauthorized reports whether err is an API error caused by missing or
+// invalid credentials.
+func IsUnauthorized(err error) bool {
+	return hasStatusCode(err, http.StatusUnauthorized)
+}
@@ -0,0 +1,997 @@
+// Package fake provides an in-memory implementation of
+// client.OpenVPNCloudAPI for tests.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+)
+
+// Client is an in-memory OpenVPN Cloud tenant. It follows the same rules as
+// the API, such as removing the routes and connectors of a network when the
+// network is deleted.
+type Client struct {
+	mutex            sync.Mutex
+	networks         []client.Network
+	routes           map[string][]client.Route
+	connectors       []client.Connector
+	hosts            []client.Host
+	users            []client.User
+	userGroups       []client.UserGroup
+	regions          []client.VpnRegion
+	dnsRecords       []client.DnsRecord
+	accessGroups     []client.AccessGroup
+	locationContexts []client.LocationContext
+	addresses        int
+}
+
+var _ client.OpenVPNCloudAPI = (*Client)(nil)
+
+// DefaultVpnRegions are the regions every new fake tenant starts with.
+var DefaultVpnRegions = []client.VpnRegion{
+	{Id: "us-east-1", Continent: "North America", Country: "United States", CountryISO: "US", RegionName: "N. Virginia"},
+	{Id: "eu-central-1", Continent: "Europe", Country: "Germany", CountryISO: "DE", RegionName: "Frankfurt"},
+	{Id: "ap-southeast-1", Continent: "Asia", Country: "Singapore", CountryISO: "SG", RegionName: "Singapore"},
+}
+
+// DefaultUserGroupName is the name of the user group every new fake tenant
+// starts with.
+const DefaultUserGroupName = "Default"
+
+// NewClient returns an empty tenant with the default VPN regions and user
+// group.
+func NewClient() *Client {
+	c := &Client{
+		routes: map[string][]client.Route{},
+	}
+	for _, r := range DefaultVpnRegions {
+		c.PutVpnRegion(r)
+	}
+	c.PutUserGroup(client.UserGroup{
+		Name:           DefaultUserGroupName,
+		VpnRegionIds:   []string{DefaultVpnRegions[0].Id},
+		InternetAccess: "SPLIT_TUNNEL_ON",
+		MaxDevice:      3,
+		SystemSubnets:  []string{"100.96.0.0/11", "fd:0:0:8000::/49"},
+	})
+	return c
+}
+
+// PutVpnRegion adds a VPN region to the tenant, or replaces the one with the
+// same id.
+func (c *Client) PutVpnRegion(region client.VpnRegion) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, r := range c.regions {
+		if r.Id == region.Id {
+			c.regions[i] = region
+			return
+		}
+	}
+	c.regions = append(c.regions, region)
+}
+
+// PutUserGroup adds a user group to the tenant, or replaces the one with the
+// same id. An id is generated if the group does not have one.
+func (c *Client) PutUserGroup(group client.UserGroup) *client.UserGroup {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if group.Id == "" {
+		group.Id = newId()
+	}
+	for i, g := range c.userGroups {
+		if g.Id == group.Id {
+			c.userGroups[i] = group
+			return &group
+		}
+	}
+	c.userGroups = append(c.userGroups, group)
+	return &group
+}
+
+func newId() string {
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+func notFound(method string, path string, format string, args ...interface{}) error {
+	return &client.APIError{
+		StatusCode: http.StatusNotFound,
+		Code:       "NOT_FOUND",
+		Message:    fmt.Sprintf(format, args...),
+		Method:     method,
+		Path:       path,
+	}
+}
+
+// nextAddresses returns a new pair of IPV4 and IPV6 addresses from the
+// tenant's subnets. The caller must hold the mutex.
+func (c *Client) nextAddresses() (string, string) {
+	c.addresses++
+	return fmt.Sprintf("100.96.%d.%d", c.addresses/250, c.addresses%250+1), fmt.Sprintf("fd:0:0:8000::%x", c.addresses)
+}
+
+// nextSubnets returns new system subnets for a network or host. The caller
+// must hold the mutex.
+func (c *Client) nextSubnets() []string {
+	c.addresses++
+	return []string{fmt.Sprintf("100.%d.%d.0/24", 97+c.addresses/250, c.addresses%250), fmt.Sprintf("fd:0:0:%x::/64", 0x8100+c.addresses)}
+}
+
+// newConnector registers a connector for the given network item. The caller
+// must hold the mutex.
+func (c *Client) newConnector(connector client.Connector, networkItemId string, networkItemType string) client.Connector {
+	connector.Id = newId()
+	connector.NetworkItemId = networkItemId
+	connector.NetworkItemType = networkItemType
+	connector.IPv4Address, connector.IPv6Address = c.nextAddresses()
+	c.connectors = append(c.connectors, connector)
+	return connector
+}
+
+func (c *Client) itemConnectors(networkItemId string) []client.Connector {
+	connectors := []client.Connector{}
+	for _, conn := range c.connectors {
+		if conn.NetworkItemId == networkItemId {
+			connectors = append(connectors, conn)
+		}
+	}
+	return connectors
+}
+
+func (c *Client) removeItemConnectors(networkItemId string) {
+	connectors := c.connectors[:0]
+	for _, conn := range c.connectors {
+		if conn.NetworkItemId != networkItemId {
+			connectors = append(connectors, conn)
+		}
+	}
+	c.connectors = connectors
+}
+
+// GetAccessGroups returns every access group of the tenant.
+func (c *Client) GetAccessGroups(ctx context.Context) ([]client.AccessGroup, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]client.AccessGroup{}, c.accessGroups...), nil
+}
+
+func (c *Client) GetAccessGroupByName(ctx context.Context, name string) (*client.AccessGroup, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, ag := range c.accessGroups {
+		if ag.Name == name {
+			return &ag, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) GetAccessGroupById(ctx context.Context, accessGroupId string) (*client.AccessGroup, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, ag := range c.accessGroups {
+		if ag.Id == accessGroupId {
+			return &ag, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) CreateAccessGroup(ctx context.Context, accessGroup client.AccessGroup) (*client.AccessGroup, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	accessGroup.Id = newId()
+	c.accessGroups = append(c.accessGroups, accessGroup)
+	return &accessGroup, nil
+}
+
+func (c *Client) UpdateAccessGroup(ctx context.Context, accessGroup client.AccessGroup) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, ag := range c.accessGroups {
+		if ag.Id == accessGroup.Id {
+			c.accessGroups[i] = accessGroup
+			return nil
+		}
+	}
+	return notFound(http.MethodPut, "/api/beta/access-groups/"+accessGroup.Id, "access group %s not found", accessGroup.Id)
+}
+
+func (c *Client) DeleteAccessGroup(ctx context.Context, accessGroupId string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, ag := range c.accessGroups {
+		if ag.Id == accessGroupId {
+			c.accessGroups = append(c.accessGroups[:i], c.accessGroups[i+1:]...)
+			return nil
+		}
+	}
+	return notFound(http.MethodDelete, "/api/beta/access-groups/"+accessGroupId, "access group %s not found", accessGroupId)
+}
+
+func (c *Client) GetConnectors(ctx context.Context) ([]client.Connector, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]client.Connector{}, c.connectors...), nil
+}
+
+func (c *Client) GetConnectorByName(ctx context.Context, name string) (*client.Connector, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, conn := range c.connectors {
+		if conn.Name == name {
+			return &conn, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) GetConnectorById(ctx context.Context, connectorId string) (*client.Connector, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, conn := range c.connectors {
+		if conn.Id == connectorId {
+			return &conn, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) GetConnectorsForNetwork(ctx context.Context, networkId string) ([]client.Connector, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.itemConnectors(networkId), nil
+}
+
+func (c *Client) AddConnector(ctx context.Context, connector client.Connector, networkItemId string) (*client.Connector, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	switch connector.NetworkItemType {
+	case client.NetworkItemTypeNetwork:
+		if c.networkIndex(networkItemId) < 0 {
+			return nil, notFound(http.MethodPost, "/api/beta/connectors", "network %s not found", networkItemId)
+		}
+	case client.NetworkItemTypeHost:
+		if c.hostIndex(networkItemId) < 0 {
+			return nil, notFound(http.MethodPost, "/api/beta/connectors", "host %s not found", networkItemId)
+		}
+	default:
+		return nil, &client.APIError{
+			StatusCode: http.StatusBadRequest,
+			Code:       "VALIDATION_ERROR",
+			Message:    fmt.Sprintf("invalid network item type %q", connector.NetworkItemType),
+			Method:     http.MethodPost,
+			Path:       "/api/beta/connectors",
+		}
+	}
+	conn := c.newConnector(connector, networkItemId, connector.NetworkItemType)
+	return &conn, nil
+}
+
+func (c *Client) DeleteConnector(ctx context.Context, connectorId string, networkItemId string, networkItemType string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, conn := range c.connectors {
+		if conn.Id == connectorId && conn.NetworkItemId == networkItemId {
+			c.connectors = append(c.connectors[:i], c.connectors[i+1:]...)
+			return nil
+		}
+	}
+	return notFound(http.MethodDelete, "/api/beta/connectors/"+connectorId, "connector %s not found", connectorId)
+}
+
+func (c *Client) CreateDnsRecord(ctx context.Context, record client.DnsRecord) (*client.DnsRecord, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	record.Id = newId()
+	c.dnsRecords = append(c.dnsRecords, record)
+	return &record, nil
+}
+
+// GetDnsRecords returns every DNS record of the tenant.
+func (c *Client) GetDnsRecords(ctx context.Context) ([]client.DnsRecord, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]client.DnsRecord{}, c.dnsRecords...), nil
+}
+
+func (c *Client) GetDnsRecord(ctx context.Context, recordId string) (*client.DnsRecord, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, r := range c.dnsRecords {
+		if r.Id == recordId {
+			return &r, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) UpdateDnsRecord(ctx context.Context, record client.DnsRecord) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, r := range c.dnsRecords {
+		if r.Id == record.Id {
+			c.dnsRecords[i] = record
+			return nil
+		}
+	}
+	return notFound(http.MethodPut, "/api/beta/dns-records/"+record.Id, "DNS record %s not found", record.Id)
+}
+
+func (c *Client) DeleteDnsRecord(ctx context.Context, recordId string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, r := range c.dnsRecords {
+		if r.Id == recordId {
+			c.dnsRecords = append(c.dnsRecords[:i], c.dnsRecords[i+1:]...)
+			return nil
+		}
+	}
+	return notFound(http.MethodDelete, "/api/beta/dns-records/"+recordId, "DNS record %s not found", recordId)
+}
+
+func (c *Client) hostIndex(hostId string) int {
+	for i, h := range c.hosts {
+		if h.Id == hostId {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *Client) host(i int) client.Host {
+	h := c.hosts[i]
+	h.SystemSubnets = append([]string{}, h.SystemSubnets...)
+	h.Connectors = c.itemConnectors(h.Id)
+	return h
+}
+
+func (c *Client) GetHosts(ctx context.Context) ([]client.Host, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	hosts := []client.Host{}
+	for i := range c.hosts {
+		hosts = append(hosts, c.host(i))
+	}
+	return hosts, nil
+}
+
+func (c *Client) GetHostByName(ctx context.Context, name string) (*client.Host, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, h := range c.hosts {
+		if h.Name == name {
+			host := c.host(i)
+			return &host, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) GetHostById(ctx context.Context, hostId string) (*client.Host, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	i := c.hostIndex(hostId)
+	if i < 0 {
+		return nil, nil
+	}
+	host := c.host(i)
+	return &host, nil
+}
+
+func (c *Client) CreateHost(ctx context.Context, host client.Host) (*client.Host, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	host.Id = newId()
+	host.SystemSubnets = c.nextSubnets()
+	for _, conn := range host.Connectors {
+		c.newConnector(conn, host.Id, client.NetworkItemTypeHost)
+	}
+	host.Connectors = nil
+	c.hosts = append(c.hosts, host)
+	created := c.host(len(c.hosts) - 1)
+	return &created, nil
+}
+
+func (c *Client) UpdateHost(ctx context.Context, host client.Host) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	i := c.hostIndex(host.Id)
+	if i < 0 {
+		return notFound(http.MethodPut, "/api/beta/hosts/"+host.Id, "host %s not found", host.Id)
+	}
+	c.hosts[i].Name = host.Name
+	c.hosts[i].Description = host.Description
+	c.hosts[i].InternetAccess = host.InternetAccess
+	return nil
+}
+
+func (c *Client) DeleteHost(ctx context.Context, hostId string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	i := c.hostIndex(hostId)
+	if i < 0 {
+		return notFound(http.MethodDelete, "/api/beta/hosts/"+hostId, "host %s not found", hostId)
+	}
+	c.hosts = append(c.hosts[:i], c.hosts[i+1:]...)
+	c.removeItemConnectors(hostId)
+	return nil
+}
+
+// GetLocationContexts returns every location context of the tenant.
+func (c *Client) GetLocationContexts(ctx context.Context) ([]client.LocationContext, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]client.LocationContext{}, c.locationContexts...), nil
+}
+
+func (c *Client) GetLocationContextById(ctx context.Context, locationContextId string) (*client.LocationContext, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, lc := range c.locationContexts {
+		if lc.Id == locationContextId {
+			return &lc, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) CreateLocationContext(ctx context.Context, locationContext client.LocationContext) (*client.LocationContext, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	locationContext.Id = newId()
+	c.locationContexts = append(c.locationContexts, locationContext)
+	return &locationContext, nil
+}
+
+func (c *Client) UpdateLocationContext(ctx context.Context, locationContext client.LocationContext) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, lc := range c.locationContexts {
+		if lc.Id == locationContext.Id {
+			c.locationContexts[i] = locationContext
+			return nil
+		}
+	}
+	return notFound(http.MethodPut, "/api/beta/location-contexts/"+locationContext.Id, "location context %s not found", locationContext.Id)
+}
+
+func (c *Client) DeleteLocationContext(ctx context.Context, locationContextId string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, lc := range c.locationContexts {
+		if lc.Id == locationContextId {
+			c.locationContexts = append(c.locationContexts[:i], c.locationContexts[i+1:]...)
+			return nil
+		}
+	}
+	return notFound(http.MethodDelete, "/api/beta/location-contexts/"+locationContextId, "location context %s not found", locationContextId)
+}
+
+func (c *Client) networkIndex(networkId string) int {
+	for i, n := range c.networks {
+		if n.Id == networkId {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *Client) network(i int) client.Network {
+	n := c.networks[i]
+	n.SystemSubnets = append([]string{}, n.SystemSubnets...)
+	n.Routes = append([]client.Route{}, c.routes[n.Id]...)
+	n.Connectors = c.itemConnectors(n.Id)
+	return n
+}
+
+func (c *Client) GetNetworks(ctx context.Context) ([]client.Network, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	networks := []client.Network{}
+	for i := range c.networks {
+		networks = append(networks, c.network(i))
+	}
+	return networks, nil
+}
+
+func (c *Client) GetNetworkByName(ctx context.Context, name string) (*client.Network, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, n := range c.networks {
+		if n.Name == name {
+			network := c.network(i)
+			return &network, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) GetNetworkById(ctx context.Context, networkId string) (*client.Network, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	i := c.networkIndex(networkId)
+	if i < 0 {
+		return nil, nil
+	}
+	network := c.network(i)
+	return &network, nil
+}
+
+func (c *Client) CreateNetwork(ctx context.Context, network client.Network) (*client.Network, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	network.Id = newId()
+	network.SystemSubnets = c.nextSubnets()
+	for _, conn := range network.Connectors {
+		c.newConnector(conn, network.Id, client.NetworkItemTypeNetwork)
+	}
+	for _, r := range network.Routes {
+		c.routes[network.Id] = append(c.routes[network.Id], newRoute(network.Id, r))
+	}
+	network.Connectors = nil
+	network.Routes = nil
+	c.networks = append(c.networks, network)
+	created := c.network(len(c.networks) - 1)
+	return &created, nil
+}
+
+func (c *Client) UpdateNetwork(ctx context.Context, network client.Network) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	i := c.networkIndex(network.Id)
+	if i < 0 {
+		return notFound(http.MethodPut, "/api/beta/networks/"+network.Id, "network %s not found", network.Id)
+	}
+	c.networks[i].Name = network.Name
+	c.networks[i].Description = network.Description
+	c.networks[i].Egress = network.Egress
+	c.networks[i].InternetAccess = network.InternetAccess
+	return nil
+}
+
+func (c *Client) DeleteNetwork(ctx context.Context, networkId string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	i := c.networkIndex(networkId)
+	if i < 0 {
+		return notFound(http.MethodDelete, "/api/beta/networks/"+networkId, "network %s not found", networkId)
+	}
+	c.networks = append(c.networks[:i], c.networks[i+1:]...)
+	delete(c.routes, networkId)
+	c.removeItemConnectors(networkId)
+	return nil
+}
+
+// newRoute stores the value of the route in the field the API returns it in,
+// which depends on the route type.
+func newRoute(networkId string, route client.Route) client.Route {
+	r := client.Route{
+		Id:            newId(),
+		Type:          route.Type,
+		NetworkItemId: networkId,
+	}
+	setRouteValue(&r, route.Value)
+	return r
+}
+
+func setRouteValue(r *client.Route, value string) {
+	r.Subnet = ""
+	r.Domain = ""
+	if r.Type == client.RouteTypeDomain {
+		r.Domain = value
+	} else {
+		r.Subnet = value
+	}
+}
+
+func (c *Client) CreateRoute(ctx context.Context, networkId string, route client.Route) (*client.Route, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.networkIndex(networkId) < 0 {
+		return nil, notFound(http.MethodPost, "/api/beta/networks/"+networkId+"/routes", "network %s not found", networkId)
+	}
+	r := newRoute(networkId, route)
+	c.routes[networkId] = append(c.routes[networkId], r)
+	return &r, nil
+}
+
+func (c *Client) DeleteRoute(ctx context.Context, networkId string, routeId string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	routes := c.routes[networkId]
+	for i, r := range routes {
+		if r.Id == routeId {
+			c.routes[networkId] = append(routes[:i], routes[i+1:]...)
+			return nil
+		}
+	}
+	return notFound(http.MethodDelete, "/api/beta/networks/"+networkId+"/routes/"+routeId, "route %s not found", routeId)
+}
+
+func (c *Client) GetRoutes(ctx context.Context, networkId string) ([]client.Route, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.networkIndex(networkId) < 0 {
+		return nil, notFound(http.MethodGet, "/api/beta/networks/"+networkId+"/routes", "network %s not found", networkId)
+	}
+	return append([]client.Route{}, c.routes[networkId]...), nil
+}
+
+func (c *Client) GetNetworkRoute(ctx context.Context, networkId string, routeId string) (*client.Route, error) {
+	routes, err := c.GetRoutes(ctx, networkId)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range routes {
+		if r.Id == routeId {
+			return &r, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) GetRouteById(ctx context.Context, routeId string) (*client.Route, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, routes := range c.routes {
+		for _, r := range routes {
+			if r.Id == routeId {
+				return &r, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) UpdateRoute(ctx context.Context, networkId string, route client.Route) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	routes := c.routes[networkId]
+	for i, r := range routes {
+		if r.Id == route.Id {
+			routes[i].Type = route.Type
+			setRouteValue(&routes[i], route.Value)
+			return nil
+		}
+	}
+	return notFound(http.MethodPut, "/api/beta/networks/"+networkId+"/routes/"+route.Id, "route %s not found", route.Id)
+}
+
+func (c *Client) CreateUser(ctx context.Context, user client.User) (*client.User, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, u := range c.users {
+		if u.Username == user.Username {
+			return nil, &client.APIError{
+				StatusCode: http.StatusConflict,
+				Code:       "USERNAME_ALREADY_EXISTS",
+				Message:    fmt.Sprintf("user %s already exists", user.Username),
+				Method:     http.MethodPost,
+				Path:       "/api/beta/users",
+			}
+		}
+	}
+	user.Id = newId()
+	if user.Role == "" {
+		user.Role = client.UserRoleMember
+	}
+	if user.AuthType == "" {
+		user.AuthType = "LOCAL"
+	}
+	if user.GroupId == "" {
+		for _, g := range c.userGroups {
+			if g.Name == DefaultUserGroupName {
+				user.GroupId = g.Id
+			}
+		}
+	}
+	user.Status = client.UserStatusPending
+	devices := []client.Device{}
+	for _, d := range user.Devices {
+		d.Id = newId()
+		ipV4Address, ipV6Address := c.nextAddresses()
+		if d.IPv4Address == "" {
+			d.IPv4Address = ipV4Address
+		}
+		if d.IPv6Address == "" {
+			d.IPv6Address = ipV6Address
+		}
+		devices = append(devices, d)
+	}
+	user.Devices = devices
+	c.users = append(c.users, user)
+	return &user, nil
+}
+
+// GetUsers returns every user of the tenant.
+func (c *Client) GetUsers(ctx context.Context) ([]client.User, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]client.User{}, c.users...), nil
+}
+
+func (c *Client) GetUser(ctx context.Context, username string, role string) (*client.User, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, u := range c.users {
+		if u.Username == username && u.Role == role {
+			return &u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) GetUserById(ctx context.Context, userId string) (*client.User, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, u := range c.users {
+		if u.Id == userId {
+			return &u, nil
+		}
+	}
+	return nil, notFound(http.MethodGet, "/api/beta/users/"+userId, "user %s not found", userId)
+}
+
+// UpdateUser changes the names, email, group, role and status of a user. Like
+// the API, it leaves the group, role and status alone when they are empty.
+func (c *Client) UpdateUser(ctx context.Context, user client.User) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, u := range c.users {
+		if u.Id != user.Id {
+			continue
+		}
+		c.users[i].Email = user.Email
+		c.users[i].FirstName = user.FirstName
+		c.users[i].LastName = user.LastName
+		if user.GroupId != "" {
+			c.users[i].GroupId = user.GroupId
+		}
+		if user.Role != "" {
+			c.users[i].Role = user.Role
+		}
+		if user.Status != "" {
+			c.users[i].Status = user.Status
+		}
+		return nil
+	}
+	return notFound(http.MethodPut, "/api/beta/users/"+user.Id, "user %s not found", user.Id)
+}
+
+func (c *Client) DeleteUser(ctx context.Context, userId string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, u := range c.users {
+		if u.Id == userId {
+			c.users = append(c.users[:i], c.users[i+1:]...)
+			return nil
+		}
+	}
+	return notFound(http.MethodDelete, "/api/beta/users/"+userId, "user %s not found", userId)
+}
+
+func (c *Client) userIndex(userId string) int {
+	for i, u := range c.users {
+		if u.Id == userId {
+			return i
+		}
+	}
+	return -1
+}
+
+// reservedAddress reports whether a device other than deviceId already holds
+// the address. The caller must hold the mutex.
+func (c *Client) reservedAddress(deviceId string, address string) bool {
+	for _, u := range c.users {
+		for _, d := range u.Devices {
+			if d.Id != deviceId && address != "" && (d.IPv4Address == address || d.IPv6Address == address) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func addressConflict(method string, path string, device client.Device) error {
+	return &client.APIError{
+		StatusCode: http.StatusConflict,
+		Code:       "IP_ADDRESS_ALREADY_IN_USE",
+		Message:    fmt.Sprintf("an address of device %s is already in use", device.Name),
+		Method:     method,
+		Path:       path,
+	}
+}
+
+// CreateDevice adds a device to a user. Addresses that are not reserved are
+// assigned from the tenant's subnets.
+func (c *Client) CreateDevice(ctx context.Context, userId string, device client.Device) (*client.Device, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	path := "/api/beta/users/" + userId + "/devices"
+	i := c.userIndex(userId)
+	if i < 0 {
+		return nil, notFound(http.MethodPost, path, "user %s not found", userId)
+	}
+	if c.reservedAddress("", device.IPv4Address) || c.reservedAddress("", device.IPv6Address) {
+		return nil, addressConflict(http.MethodPost, path, device)
+	}
+	device.Id = newId()
+	ipV4Address, ipV6Address := c.nextAddresses()
+	if device.IPv4Address == "" {
+		device.IPv4Address = ipV4Address
+	}
+	if device.IPv6Address == "" {
+		device.IPv6Address = ipV6Address
+	}
+	c.users[i].Devices = append(c.users[i].Devices, device)
+	return &device, nil
+}
+
+func (c *Client) GetDevice(ctx context.Context, userId string, deviceId string) (*client.Device, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	i := c.userIndex(userId)
+	if i < 0 {
+		return nil, notFound(http.MethodGet, "/api/beta/users/"+userId, "user %s not found", userId)
+	}
+	for _, d := range c.users[i].Devices {
+		if d.Id == deviceId {
+			return &d, nil
+		}
+	}
+	return nil, nil
+}
+
+// UpdateDevice changes the name, description and addresses of a device. An
+// empty address keeps the current one.
+func (c *Client) UpdateDevice(ctx context.Context, userId string, device client.Device) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	path := "/api/beta/users/" + userId + "/devices/" + device.Id
+	i := c.userIndex(userId)
+	if i < 0 {
+		return notFound(http.MethodPut, path, "user %s not found", userId)
+	}
+	for j, d := range c.users[i].Devices {
+		if d.Id != device.Id {
+			continue
+		}
+		if c.reservedAddress(device.Id, device.IPv4Address) || c.reservedAddress(device.Id, device.IPv6Address) {
+			return addressConflict(http.MethodPut, path, device)
+		}
+		if device.IPv4Address == "" {
+			device.IPv4Address = d.IPv4Address
+		}
+		if device.IPv6Address == "" {
+			device.IPv6Address = d.IPv6Address
+		}
+		c.users[i].Devices[j] = device
+		return nil
+	}
+	return notFound(http.MethodPut, path, "device %s not found", device.Id)
+}
+
+func (c *Client) DeleteDevice(ctx context.Context, userId string, deviceId string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	path := "/api/beta/users/" + userId + "/devices/" + deviceId
+	i := c.userIndex(userId)
+	if i < 0 {
+		return notFound(http.MethodDelete, path, "user %s not found", userId)
+	}
+	for j, d := range c.users[i].Devices {
+		if d.Id == deviceId {
+			c.users[i].Devices = append(c.users[i].Devices[:j], c.users[i].Devices[j+1:]...)
+			return nil
+		}
+	}
+	return notFound(http.MethodDelete, path, "device %s not found", deviceId)
+}
+
+// GetUserGroups returns every user group of the tenant.
+func (c *Client) GetUserGroups(ctx context.Context) ([]client.UserGroup, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]client.UserGroup{}, c.userGroups...), nil
+}
+
+func (c *Client) GetUserGroup(ctx context.Context, name string) (*client.UserGroup, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, g := range c.userGroups {
+		if g.Name == name {
+			return &g, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) GetUserGroupById(ctx context.Context, userGroupId string) (*client.UserGroup, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, g := range c.userGroups {
+		if g.Id == userGroupId {
+			return &g, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) CreateUserGroup(ctx context.Context, userGroup client.UserGroup) (*client.UserGroup, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	userGroup.Id = newId()
+	if len(userGroup.SystemSubnets) == 0 {
+		userGroup.SystemSubnets = c.nextSubnets()
+	}
+	c.userGroups = append(c.userGroups, userGroup)
+	return &userGroup, nil
+}
+
+func (c *Client) UpdateUserGroup(ctx context.Context, userGroup client.UserGroup) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, g := range c.userGroups {
+		if g.Id == userGroup.Id {
+			if len(userGroup.SystemSubnets) == 0 {
+				userGroup.SystemSubnets = g.SystemSubnets
+			}
+			c.userGroups[i] = userGroup
+			return nil
+		}
+	}
+	return notFound(http.MethodPut, "/api/beta/user-groups/"+userGroup.Id, "user group %s not found", userGroup.Id)
+}
+
+// DeleteUserGroup removes a user group and moves its users to the default
+// group, as the API does.
+func (c *Client) DeleteUserGroup(ctx context.Context, userGroupId string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	defaultGroupId := ""
+	for _, g := range c.userGroups {
+		if g.Name == DefaultUserGroupName {
+			defaultGroupId = g.Id
+		}
+	}
+	for i, g := range c.userGroups {
+		if g.Id == userGroupId {
+			c.userGroups = append(c.userGroups[:i], c.userGroups[i+1:]...)
+			for j := range c.users {
+				if c.users[j].GroupId == userGroupId {
+					c.users[j].GroupId = defaultGroupId
+				}
+			}
+			return nil
+		}
+	}
+	return notFound(http.MethodDelete, "/api/beta/user-groups/"+userGroupId, "user group %s not found", userGroupId)
+}
+
+// GetVpnRegions returns every VPN region of the tenant.
+func (c *Client) GetVpnRegions(ctx context.Context) ([]client.VpnRegion, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]client.VpnRegion{}, c.regions...), nil
+}
+
+func (c *Client) GetVpnRegion(ctx context.Context, regionId string) (*client.VpnRegion, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, r := range c.regions {
+		if r.Id == regionId {
+			return &r, nil
+		}
+	}
+	return nil, nil
+}
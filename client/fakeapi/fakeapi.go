@@ -0,0 +1,627 @@
+// Package fakeapi serves the OpenVPN Cloud beta API from an in-process HTTP
+// server backed by the in-memory tenant of the client/fake package, so that
+// client.Client and the provider can be tested end to end without a real
+// tenant.
+package fakeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client/fake"
+)
+
+const (
+	DefaultClientId     = "fake-client-id"
+	DefaultClientSecret = "fake-client-secret"
+
+	// DefaultTokenLifetime is the number of seconds an access token issued by
+	// the server is valid for, unless TokenLifetime is changed.
+	DefaultTokenLifetime = 3600
+)
+
+// Server is a running fake OpenVPN Cloud API. Its URL is used as the base URL
+// of the client.
+type Server struct {
+	*httptest.Server
+
+	// Backend holds the state of the tenant. It can be used to inspect or
+	// modify objects out of band.
+	Backend *fake.Client
+
+	ClientId     string
+	ClientSecret string
+	// TokenLifetime is the number of seconds reported as the lifetime of the
+	// access tokens issued from now on. Tokens are only revoked by
+	// ExpireTokens, whatever their reported lifetime.
+	TokenLifetime int
+
+	mutex  sync.Mutex
+	tokens map[string]bool
+}
+
+// NewServer starts a fake API for an empty tenant that accepts the default
+// client credentials. The caller must call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		Backend:       fake.NewClient(),
+		ClientId:      DefaultClientId,
+		ClientSecret:  DefaultClientSecret,
+		TokenLifetime: DefaultTokenLifetime,
+		tokens:        map[string]bool{},
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// ExpireTokens revokes every access token issued so far, so that the next
+// request of every client fails with a 401.
+func (s *Server) ExpireTokens() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.tokens = map[string]bool{}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/beta/")
+	if path == r.URL.Path {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("no endpoint at %s", r.URL.Path))
+		return
+	}
+	if path == "oauth/token" {
+		s.issueToken(w, r)
+		return
+	}
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Full authentication is required to access this resource")
+		return
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	switch segments[0] {
+	case "networks":
+		if len(segments) >= 3 && segments[2] == "routes" {
+			s.serveRoutes(w, r, segments[1], segments[3:])
+			return
+		}
+		s.serveNetworks(w, r, segments[1:])
+	case "connectors":
+		s.serveConnectors(w, r, segments[1:])
+	case "hosts":
+		s.serveHosts(w, r, segments[1:])
+	case "users":
+		s.serveUsers(w, r, segments[1:])
+	case "user-groups":
+		s.serveUserGroups(w, r, segments[1:])
+	case "regions":
+		s.serveRegions(w, r, segments[1:])
+	case "dns-records":
+		s.serveDnsRecords(w, r, segments[1:])
+	case "access-groups":
+		s.serveAccessGroups(w, r, segments[1:])
+	case "location-contexts":
+		s.serveLocationContexts(w, r, segments[1:])
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("no endpoint at %s", r.URL.Path))
+	}
+}
+
+func (s *Server) issueToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, r)
+		return
+	}
+	clientId, clientSecret, ok := r.BasicAuth()
+	if !ok || clientId != s.ClientId || clientSecret != s.ClientSecret {
+		writeError(w, http.StatusUnauthorized, "invalid_client", "Bad client credentials")
+		return
+	}
+	token, err := uuid.GenerateUUID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+		return
+	}
+	s.mutex.Lock()
+	s.tokens[token] = true
+	lifetime := s.TokenLifetime
+	s.mutex.Unlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": token,
+		"token_type":   "bearer",
+		"expires_in":   lifetime,
+		"scope":        "default",
+	})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.tokens[token]
+}
+
+func (s *Server) serveNetworks(w http.ResponseWriter, r *http.Request, segments []string) {
+	ctx := r.Context()
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		networks, err := s.Backend.GetNetworks(ctx)
+		filtered := []client.Network{}
+		for _, n := range networks {
+			if matchesFilter(r, "name", n.Name) {
+				filtered = append(filtered, n)
+			}
+		}
+		writeList(w, r, filtered, err)
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		network, err := s.Backend.GetNetworkById(ctx, segments[0])
+		writeFound(w, r, network, network != nil, err)
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		var network client.Network
+		if !readJSON(w, r, &network) {
+			return
+		}
+		created, err := s.Backend.CreateNetwork(ctx, network)
+		writeObject(w, http.StatusCreated, created, err)
+	case len(segments) == 1 && r.Method == http.MethodPut:
+		var network client.Network
+		if !readJSON(w, r, &network) {
+			return
+		}
+		network.Id = segments[0]
+		err := s.Backend.UpdateNetwork(ctx, network)
+		if err == nil {
+			updated, _ := s.Backend.GetNetworkById(ctx, network.Id)
+			writeObject(w, http.StatusOK, updated, nil)
+			return
+		}
+		writeObject(w, http.StatusOK, nil, err)
+	case len(segments) == 1 && r.Method == http.MethodDelete:
+		writeObject(w, http.StatusNoContent, nil, s.Backend.DeleteNetwork(ctx, segments[0]))
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (s *Server) serveRoutes(w http.ResponseWriter, r *http.Request, networkId string, segments []string) {
+	ctx := r.Context()
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		routes, err := s.Backend.GetRoutes(ctx, networkId)
+		writeList(w, r, routes, err)
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		var route client.Route
+		if !readJSON(w, r, &route) {
+			return
+		}
+		created, err := s.Backend.CreateRoute(ctx, networkId, route)
+		writeObject(w, http.StatusCreated, created, err)
+	case len(segments) == 1 && r.Method == http.MethodPut:
+		var route client.Route
+		if !readJSON(w, r, &route) {
+			return
+		}
+		route.Id = segments[0]
+		err := s.Backend.UpdateRoute(ctx, networkId, route)
+		if err == nil {
+			updated, _ := s.Backend.GetNetworkRoute(ctx, networkId, route.Id)
+			writeObject(w, http.StatusOK, updated, nil)
+			return
+		}
+		writeObject(w, http.StatusOK, nil, err)
+	case len(segments) == 1 && r.Method == http.MethodDelete:
+		writeObject(w, http.StatusNoContent, nil, s.Backend.DeleteRoute(ctx, networkId, segments[0]))
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (s *Server) serveConnectors(w http.ResponseWriter, r *http.Request, segments []string) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		connectors, err := s.Backend.GetConnectors(ctx)
+		filtered := []client.Connector{}
+		for _, c := range connectors {
+			if matchesFilter(r, "name", c.Name) {
+				filtered = append(filtered, c)
+			}
+		}
+		writeList(w, r, filtered, err)
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		connector, err := s.Backend.GetConnectorById(ctx, segments[0])
+		writeFound(w, r, connector, connector != nil, err)
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		var connector client.Connector
+		if !readJSON(w, r, &connector) {
+			return
+		}
+		connector.NetworkItemType = query.Get("networkItemType")
+		created, err := s.Backend.AddConnector(ctx, connector, query.Get("networkItemId"))
+		writeObject(w, http.StatusCreated, created, err)
+	case len(segments) == 1 && r.Method == http.MethodDelete:
+		err := s.Backend.DeleteConnector(ctx, segments[0], query.Get("networkItemId"), query.Get("networkItemType"))
+		writeObject(w, http.StatusNoContent, nil, err)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (s *Server) serveHosts(w http.ResponseWriter, r *http.Request, segments []string) {
+	ctx := r.Context()
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		hosts, err := s.Backend.GetHosts(ctx)
+		filtered := []client.Host{}
+		for _, h := range hosts {
+			if matchesFilter(r, "name", h.Name) {
+				filtered = append(filtered, h)
+			}
+		}
+		writeList(w, r, filtered, err)
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		host, err := s.Backend.GetHostById(ctx, segments[0])
+		writeFound(w, r, host, host != nil, err)
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		var host client.Host
+		if !readJSON(w, r, &host) {
+			return
+		}
+		created, err := s.Backend.CreateHost(ctx, host)
+		writeObject(w, http.StatusCreated, created, err)
+	case len(segments) == 1 && r.Method == http.MethodPut:
+		var host client.Host
+		if !readJSON(w, r, &host) {
+			return
+		}
+		host.Id = segments[0]
+		err := s.Backend.UpdateHost(ctx, host)
+		if err == nil {
+			updated, _ := s.Backend.GetHostById(ctx, host.Id)
+			writeObject(w, http.StatusOK, updated, nil)
+			return
+		}
+		writeObject(w, http.StatusOK, nil, err)
+	case len(segments) == 1 && r.Method == http.MethodDelete:
+		writeObject(w, http.StatusNoContent, nil, s.Backend.DeleteHost(ctx, segments[0]))
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (s *Server) serveUsers(w http.ResponseWriter, r *http.Request, segments []string) {
+	ctx := r.Context()
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		users, err := s.Backend.GetUsers(ctx)
+		filtered := []client.User{}
+		for _, u := range users {
+			if matchesFilter(r, "username", u.Username) {
+				filtered = append(filtered, u)
+			}
+		}
+		writeList(w, r, filtered, err)
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		var user client.User
+		if !readJSON(w, r, &user) {
+			return
+		}
+		created, err := s.Backend.CreateUser(ctx, user)
+		writeObject(w, http.StatusCreated, created, err)
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		user, err := s.Backend.GetUserById(ctx, segments[0])
+		writeObject(w, http.StatusOK, user, err)
+	case len(segments) == 1 && r.Method == http.MethodPut:
+		var user client.User
+		if !readJSON(w, r, &user) {
+			return
+		}
+		user.Id = segments[0]
+		err := s.Backend.UpdateUser(ctx, user)
+		if err == nil {
+			updated, _ := s.Backend.GetUserById(ctx, user.Id)
+			writeObject(w, http.StatusOK, updated, nil)
+			return
+		}
+		writeObject(w, http.StatusOK, nil, err)
+	case len(segments) == 2 && segments[1] == "devices" && r.Method == http.MethodPost:
+		var device client.Device
+		if !readJSON(w, r, &device) {
+			return
+		}
+		created, err := s.Backend.CreateDevice(ctx, segments[0], device)
+		writeObject(w, http.StatusCreated, created, err)
+	case len(segments) == 3 && segments[1] == "devices" && r.Method == http.MethodPut:
+		var device client.Device
+		if !readJSON(w, r, &device) {
+			return
+		}
+		device.Id = segments[2]
+		err := s.Backend.UpdateDevice(ctx, segments[0], device)
+		if err == nil {
+			updated, _ := s.Backend.GetDevice(ctx, segments[0], device.Id)
+			writeObject(w, http.StatusOK, updated, nil)
+			return
+		}
+		writeObject(w, http.StatusOK, nil, err)
+	case len(segments) == 3 && segments[1] == "devices" && r.Method == http.MethodDelete:
+		writeObject(w, http.StatusNoContent, nil, s.Backend.DeleteDevice(ctx, segments[0], segments[2]))
+	case len(segments) == 1 && r.Method == http.MethodDelete:
+		writeObject(w, http.StatusNoContent, nil, s.Backend.DeleteUser(ctx, segments[0]))
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (s *Server) serveUserGroups(w http.ResponseWriter, r *http.Request, segments []string) {
+	ctx := r.Context()
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		userGroups, err := s.Backend.GetUserGroups(ctx)
+		filtered := []client.UserGroup{}
+		for _, ug := range userGroups {
+			if matchesFilter(r, "name", ug.Name) {
+				filtered = append(filtered, ug)
+			}
+		}
+		writeList(w, r, filtered, err)
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		userGroup, err := s.Backend.GetUserGroupById(ctx, segments[0])
+		writeFound(w, r, userGroup, userGroup != nil, err)
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		var userGroup client.UserGroup
+		if !readJSON(w, r, &userGroup) {
+			return
+		}
+		created, err := s.Backend.CreateUserGroup(ctx, userGroup)
+		writeObject(w, http.StatusCreated, created, err)
+	case len(segments) == 1 && r.Method == http.MethodPut:
+		var userGroup client.UserGroup
+		if !readJSON(w, r, &userGroup) {
+			return
+		}
+		userGroup.Id = segments[0]
+		err := s.Backend.UpdateUserGroup(ctx, userGroup)
+		if err == nil {
+			updated, _ := s.Backend.GetUserGroupById(ctx, userGroup.Id)
+			writeObject(w, http.StatusOK, updated, nil)
+			return
+		}
+		writeObject(w, http.StatusOK, nil, err)
+	case len(segments) == 1 && r.Method == http.MethodDelete:
+		writeObject(w, http.StatusNoContent, nil, s.Backend.DeleteUserGroup(ctx, segments[0]))
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (s *Server) serveRegions(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) != 0 || r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+	regions, err := s.Backend.GetVpnRegions(r.Context())
+	writeList(w, r, regions, err)
+}
+
+func (s *Server) serveDnsRecords(w http.ResponseWriter, r *http.Request, segments []string) {
+	ctx := r.Context()
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		records, err := s.Backend.GetDnsRecords(ctx)
+		writeList(w, r, records, err)
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		var record client.DnsRecord
+		if !readJSON(w, r, &record) {
+			return
+		}
+		created, err := s.Backend.CreateDnsRecord(ctx, record)
+		writeObject(w, http.StatusCreated, created, err)
+	case len(segments) == 1 && r.Method == http.MethodPut:
+		var record client.DnsRecord
+		if !readJSON(w, r, &record) {
+			return
+		}
+		record.Id = segments[0]
+		err := s.Backend.UpdateDnsRecord(ctx, record)
+		writeObject(w, http.StatusOK, &record, err)
+	case len(segments) == 1 && r.Method == http.MethodDelete:
+		writeObject(w, http.StatusNoContent, nil, s.Backend.DeleteDnsRecord(ctx, segments[0]))
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (s *Server) serveAccessGroups(w http.ResponseWriter, r *http.Request, segments []string) {
+	ctx := r.Context()
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		accessGroups, err := s.Backend.GetAccessGroups(ctx)
+		filtered := []client.AccessGroup{}
+		for _, ag := range accessGroups {
+			if matchesFilter(r, "name", ag.Name) {
+				filtered = append(filtered, ag)
+			}
+		}
+		writeList(w, r, filtered, err)
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		accessGroup, err := s.Backend.GetAccessGroupById(ctx, segments[0])
+		writeFound(w, r, accessGroup, accessGroup != nil, err)
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		var accessGroup client.AccessGroup
+		if !readJSON(w, r, &accessGroup) {
+			return
+		}
+		created, err := s.Backend.CreateAccessGroup(ctx, accessGroup)
+		writeObject(w, http.StatusCreated, created, err)
+	case len(segments) == 1 && r.Method == http.MethodPut:
+		var accessGroup client.AccessGroup
+		if !readJSON(w, r, &accessGroup) {
+			return
+		}
+		accessGroup.Id = segments[0]
+		err := s.Backend.UpdateAccessGroup(ctx, accessGroup)
+		writeObject(w, http.StatusOK, &accessGroup, err)
+	case len(segments) == 1 && r.Method == http.MethodDelete:
+		writeObject(w, http.StatusNoContent, nil, s.Backend.DeleteAccessGroup(ctx, segments[0]))
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (s *Server) serveLocationContexts(w http.ResponseWriter, r *http.Request, segments []string) {
+	ctx := r.Context()
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		locationContexts, err := s.Backend.GetLocationContexts(ctx)
+		filtered := []client.LocationContext{}
+		for _, lc := range locationContexts {
+			if matchesFilter(r, "name", lc.Name) {
+				filtered = append(filtered, lc)
+			}
+		}
+		writeList(w, r, filtered, err)
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		locationContext, err := s.Backend.GetLocationContextById(ctx, segments[0])
+		writeFound(w, r, locationContext, locationContext != nil, err)
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		var locationContext client.LocationContext
+		if !readJSON(w, r, &locationContext) {
+			return
+		}
+		created, err := s.Backend.CreateLocationContext(ctx, locationContext)
+		writeObject(w, http.StatusCreated, created, err)
+	case len(segments) == 1 && r.Method == http.MethodPut:
+		var locationContext client.LocationContext
+		if !readJSON(w, r, &locationContext) {
+			return
+		}
+		locationContext.Id = segments[0]
+		err := s.Backend.UpdateLocationContext(ctx, locationContext)
+		writeObject(w, http.StatusOK, &locationContext, err)
+	case len(segments) == 1 && r.Method == http.MethodDelete:
+		writeObject(w, http.StatusNoContent, nil, s.Backend.DeleteLocationContext(ctx, segments[0]))
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// matchesFilter reports whether value passes the filter set by the query
+// parameter param of r. Every value passes when the parameter is absent.
+func matchesFilter(r *http.Request, param string, value string) bool {
+	filter, ok := r.URL.Query()[param]
+	return !ok || filter[0] == value
+}
+
+// writeFound writes v, or a 404 if the backend did not find it.
+func writeFound(w http.ResponseWriter, r *http.Request, v interface{}, found bool, err error) {
+	if err == nil && !found {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("no object at %s", r.URL.Path))
+		return
+	}
+	writeObject(w, http.StatusOK, v, err)
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code string, message string) {
+	writeJSON(w, statusCode, map[string]string{
+		"errorCode":    code,
+		"errorMessage": message,
+	})
+}
+
+func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", fmt.Sprintf("%s is not supported on %s", r.Method, r.URL.Path))
+}
+
+// writeObject writes v with the given status code, or the error returned by
+// the backend.
+func writeObject(w http.ResponseWriter, statusCode int, v interface{}, err error) {
+	if err != nil {
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) {
+			writeError(w, apiErr.StatusCode, apiErr.Code, apiErr.Message)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+		return
+	}
+	if statusCode == http.StatusNoContent {
+		w.WriteHeader(statusCode)
+		return
+	}
+	writeJSON(w, statusCode, v)
+}
+
+// writeList writes a list of items. Requests with a page parameter get a page
+// envelope, like the paginated endpoints of the API, and the others get a
+// bare array.
+func writeList(w http.ResponseWriter, r *http.Request, items interface{}, err error) {
+	if err != nil {
+		writeObject(w, http.StatusOK, nil, err)
+		return
+	}
+	query := r.URL.Query()
+	if query.Get("page") == "" {
+		writeJSON(w, http.StatusOK, items)
+		return
+	}
+	raw, err := json.Marshal(items)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+		return
+	}
+	var all []json.RawMessage
+	json.Unmarshal(raw, &all)
+	pageNumber, err := strconv.Atoi(query.Get("page"))
+	if err != nil || pageNumber < 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_PAGE", fmt.Sprintf("invalid page %q", query.Get("page")))
+		return
+	}
+	size := 10
+	if query.Get("size") != "" {
+		size, err = strconv.Atoi(query.Get("size"))
+		if err != nil || size < 1 {
+			writeError(w, http.StatusBadRequest, "INVALID_PAGE_SIZE", fmt.Sprintf("invalid page size %q", query.Get("size")))
+			return
+		}
+	}
+	totalPages := (len(all) + size - 1) / size
+	content := []json.RawMessage{}
+	if start := pageNumber * size; start < len(all) {
+		end := start + size
+		if end > len(all) {
+			end = len(all)
+		}
+		content = all[start:end]
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"content":       content,
+		"number":        pageNumber,
+		"size":          size,
+		"totalElements": len(all),
+		"totalPages":    totalPages,
+		"last":          pageNumber+1 >= totalPages,
+	})
+}
@@ -2,9 +2,10 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
+	"net/url"
 )
 
 type Host struct {
@@ -16,25 +17,18 @@ type Host struct {
 	Connectors     []Connector `json:"connectors"`
 }
 
-func (c *Client) GetHosts() ([]Host, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/beta/hosts", c.BaseURL), nil)
-	if err != nil {
-		return nil, err
-	}
-	body, err := c.DoRequest(req)
-	if err != nil {
-		return nil, err
-	}
+func (c *Client) GetHosts(ctx context.Context) ([]Host, error) {
 	var hosts []Host
-	err = json.Unmarshal(body, &hosts)
+	err := c.getAll(ctx, c.apiPath("hosts"), &hosts)
 	if err != nil {
 		return nil, err
 	}
 	return hosts, nil
 }
 
-func (c *Client) GetHostByName(name string) (*Host, error) {
-	hosts, err := c.GetHosts()
+func (c *Client) GetHostByName(ctx context.Context, name string) (*Host, error) {
+	var hosts []Host
+	err := c.getFiltered(ctx, "hosts", url.Values{"name": {name}}, &hosts)
 	if err != nil {
 		return nil, err
 	}
@@ -46,58 +40,73 @@ func (c *Client) GetHostByName(name string) (*Host, error) {
 	return nil, nil
 }
 
-func (c *Client) GetHostById(hostId string) (*Host, error) {
-	hosts, err := c.GetHosts()
-	if err != nil {
-		return nil, err
-	}
-	for _, h := range hosts {
-		if h.Id == hostId {
-			return &h, nil
+func (c *Client) GetHostById(ctx context.Context, hostId string) (*Host, error) {
+	var host Host
+	found, err := c.getById(ctx, "hosts", hostId, &host, func() (bool, error) {
+		hosts, err := c.GetHosts(ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, h := range hosts {
+			if h.Id == hostId {
+				host = h
+				return true, nil
+			}
 		}
+		return false, nil
+	})
+	if err != nil || !found {
+		return nil, err
 	}
-	return nil, nil
+	return &host, nil
 }
 
-func (c *Client) CreateHost(host Host) (*Host, error) {
+func (c *Client) CreateHost(ctx context.Context, host Host) (*Host, error) {
 	hostJson, err := json.Marshal(host)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/beta/hosts", c.BaseURL), bytes.NewBuffer(hostJson))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL("hosts"), bytes.NewBuffer(hostJson))
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.DoRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	var h Host
-	err = json.Unmarshal(body, &h)
+	var h *Host
+	err = c.createIdempotently(ctx, func() error {
+		body, err := c.doMutation(req, "hosts", "connectors")
+		if err != nil {
+			return err
+		}
+		h = &Host{}
+		return json.Unmarshal(body, h)
+	}, func() (bool, error) {
+		existing, err := c.GetHostByName(ctx, host.Name)
+		h = existing
+		return existing != nil, err
+	})
 	if err != nil {
 		return nil, err
 	}
-	return &h, nil
+	return h, nil
 }
 
-func (c *Client) UpdateHost(host Host) error {
+func (c *Client) UpdateHost(ctx context.Context, host Host) error {
 	hostJson, err := json.Marshal(host)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/beta/hosts/%s", c.BaseURL, host.Id), bytes.NewBuffer(hostJson))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.apiURL("hosts/%s", host.Id), bytes.NewBuffer(hostJson))
 	if err != nil {
 		return err
 	}
-	_, err = c.DoRequest(req)
+	_, err = c.doMutation(req, "hosts")
 	return err
 }
 
-func (c *Client) DeleteHost(hostId string) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/beta/hosts/%s", c.BaseURL, hostId), nil)
+func (c *Client) DeleteHost(ctx context.Context, hostId string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.apiURL("hosts/%s", hostId), nil)
 	if err != nil {
 		return err
 	}
-	_, err = c.DoRequest(req)
+	_, err = c.doMutation(req, "hosts", "connectors")
 	return err
 }
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// LocationContext is a connection policy that applies to the users of some
+// user groups, based on the address and country they connect from.
+type LocationContext struct {
+	Id            string        `json:"id,omitempty"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description"`
+	UserGroupsIds []string      `json:"userGroupsIds"`
+	IpCheck       *IpCheck      `json:"ipCheck,omitempty"`
+	CountryCheck  *CountryCheck `json:"countryCheck,omitempty"`
+	DefaultCheck  DefaultCheck  `json:"defaultCheck"`
+}
+
+// IpCheck allows or denies connections from the subnets in Ips.
+type IpCheck struct {
+	Allowed bool `json:"allowed"`
+	Ips     []Ip `json:"ips"`
+}
+
+type Ip struct {
+	Ip          string `json:"ip"`
+	Description string `json:"description"`
+}
+
+// CountryCheck allows or denies connections from the countries in
+// Countries, given as ISO 3166-1 alpha-2 codes like VpnRegion.CountryISO.
+type CountryCheck struct {
+	Allowed   bool     `json:"allowed"`
+	Countries []string `json:"countries"`
+}
+
+// DefaultCheck decides the connections that no other check matched.
+type DefaultCheck struct {
+	Allowed bool `json:"allowed"`
+}
+
+func (c *Client) GetLocationContexts(ctx context.Context) ([]LocationContext, error) {
+	var locationContexts []LocationContext
+	err := c.getAll(ctx, c.apiPath("location-contexts"), &locationContexts)
+	if err != nil {
+		return nil, err
+	}
+	return locationContexts, nil
+}
+
+func (c *Client) GetLocationContextByName(ctx context.Context, name string) (*LocationContext, error) {
+	var locationContexts []LocationContext
+	err := c.getFiltered(ctx, "location-contexts", url.Values{"name": {name}}, &locationContexts)
+	if err != nil {
+		return nil, err
+	}
+	for _, lc := range locationContexts {
+		if lc.Name == name {
+			return &lc, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) GetLocationContextById(ctx context.Context, locationContextId string) (*LocationContext, error) {
+	var locationContext LocationContext
+	found, err := c.getById(ctx, "location-contexts", locationContextId, &locationContext, func() (bool, error) {
+		locationContexts, err := c.GetLocationContexts(ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, lc := range locationContexts {
+			if lc.Id == locationContextId {
+				locationContext = lc
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil || !found {
+		return nil, err
+	}
+	return &locationContext, nil
+}
+
+func (c *Client) CreateLocationContext(ctx context.Context, locationContext LocationContext) (*LocationContext, error) {
+	locationContextJson, err := json.Marshal(locationContext)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL("location-contexts"), bytes.NewBuffer(locationContextJson))
+	if err != nil {
+		return nil, err
+	}
+	var lc *LocationContext
+	err = c.createIdempotently(ctx, func() error {
+		body, err := c.doMutation(req, "location-contexts")
+		if err != nil {
+			return err
+		}
+		lc = &LocationContext{}
+		return json.Unmarshal(body, lc)
+	}, func() (bool, error) {
+		existing, err := c.GetLocationContextByName(ctx, locationContext.Name)
+		lc = existing
+		return existing != nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lc, nil
+}
+
+func (c *Client) UpdateLocationContext(ctx context.Context, locationContext LocationContext) error {
+	locationContextJson, err := json.Marshal(locationContext)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.apiURL("location-contexts/%s", locationContext.Id), bytes.NewBuffer(locationContextJson))
+	if err != nil {
+		return err
+	}
+	_, err = c.doMutation(req, "location-contexts")
+	return err
+}
+
+func (c *Client) DeleteLocationContext(ctx context.Context, locationContextId string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.apiURL("location-contexts/%s", locationContextId), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.doMutation(req, "location-contexts")
+	return err
+}
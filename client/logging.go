@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// RequestIdHeader carries the id that identifies a request and all its
+	// retries, both in the logs and in the API.
+	RequestIdHeader = "X-Request-Id"
+
+	redacted = "***"
+)
+
+var (
+	accessTokenPattern   = regexp.MustCompile(`("access_token"\s*:\s*")[^"]*(")`)
+	authorizationPattern = regexp.MustCompile(`(?i)\b(Bearer|Basic) [A-Za-z0-9\-._~+/]+=*`)
+)
+
+// redactedError is an error whose message had the client's secrets removed.
+type redactedError struct {
+	err     error
+	message string
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+func newRequestId() string {
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return ""
+	}
+	return id
+}
+
+// addSecret registers a value that must never appear in logs or errors.
+func (c *Client) addSecret(secret string) {
+	if secret == "" {
+		return
+	}
+	c.secretsMutex.Lock()
+	defer c.secretsMutex.Unlock()
+	c.secrets = append(c.secrets, secret)
+}
+
+// redact removes credentials from s: access tokens in token responses,
+// authorization header values, and every secret known to the client.
+func (c *Client) redact(s string) string {
+	s = accessTokenPattern.ReplaceAllString(s, "${1}"+redacted+"${2}")
+	s = authorizationPattern.ReplaceAllString(s, "${1} "+redacted)
+	c.secretsMutex.Lock()
+	defer c.secretsMutex.Unlock()
+	for _, secret := range c.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return s
+}
+
+// redactError returns err with the client's secrets removed from its message.
+// The original error can still be inspected with errors.As.
+func (c *Client) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	message := c.redact(err.Error())
+	if message == err.Error() {
+		return err
+	}
+	return &redactedError{err: err, message: message}
+}
+
+// logExchange logs a request sent to the API and its outcome at DEBUG level,
+// and the headers and bodies at TRACE level.
+func (c *Client) logExchange(ctx context.Context, req *http.Request, requestBody []byte, res *http.Response, responseBody []byte, err error, latency time.Duration, attempt int) {
+	fields := []interface{}{
+		"method", req.Method,
+		"path", req.URL.Path,
+		"attempt", attempt,
+		"request_id", req.Header.Get(RequestIdHeader),
+		"latency_ms", latency.Milliseconds(),
+	}
+	if err != nil {
+		tflog.Debug(ctx, "OpenVPN Cloud API request failed", append(fields, "error", c.redact(err.Error()))...)
+	} else {
+		tflog.Debug(ctx, "OpenVPN Cloud API request completed", append(fields, "status", res.StatusCode)...)
+	}
+	tflog.Trace(ctx, "OpenVPN Cloud API request", append(fields, "headers", c.redactHeaders(req.Header), "body", c.redact(string(requestBody)))...)
+	if res != nil {
+		tflog.Trace(ctx, "OpenVPN Cloud API response", append(fields, "status", res.StatusCode, "headers", c.redactHeaders(res.Header), "body", c.redact(string(responseBody)))...)
+	}
+}
+
+func (c *Client) redactHeaders(header http.Header) map[string]string {
+	headers := map[string]string{}
+	for name, values := range header {
+		if http.CanonicalHeaderKey(name) == "Authorization" {
+			headers[name] = redacted
+			continue
+		}
+		headers[name] = c.redact(strings.Join(values, ", "))
+	}
+	return headers
+}
@@ -0,0 +1,131 @@
+package client_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tfsdklog"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client/fakeapi"
+)
+
+const testClientSecret = "s3cr3t-d0-n0t-l0g"
+
+// captureLogs returns a context whose provider logger writes every message to
+// a file, and a function that returns what was written so far.
+func captureLogs(t *testing.T) (context.Context, func() string) {
+	path := filepath.Join(t.TempDir(), "provider.log")
+	env := map[string]string{"TF_LOG": "TRACE", "TF_LOG_PATH": path, "TF_ACC_LOG_PATH": "", "TF_LOG_PATH_MASK": ""}
+	for name, value := range env {
+		previous, ok := os.LookupEnv(name)
+		if value == "" {
+			os.Unsetenv(name)
+		} else {
+			os.Setenv(name, value)
+		}
+		defer func(name string) {
+			if ok {
+				os.Setenv(name, previous)
+			} else {
+				os.Unsetenv(name)
+			}
+		}(name)
+	}
+	ctx := tfsdklog.RegisterTestSink(context.Background(), t)
+	ctx = tfsdklog.NewRootProviderLogger(ctx)
+	return ctx, func() string {
+		logs, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(logs)
+	}
+}
+
+// credentialEcho records every credential sent to the fake API, and answers
+// the requests of a path with an error whose message repeats them.
+type credentialEcho struct {
+	path       string
+	statusCode int
+
+	mutex       sync.Mutex
+	credentials []string
+}
+
+func (ce *credentialEcho) intercept(w http.ResponseWriter, r *http.Request) bool {
+	authorization := r.Header.Get("Authorization")
+	ce.mutex.Lock()
+	ce.credentials = append(ce.credentials, authorization, strings.TrimPrefix(authorization, "Bearer "))
+	ce.mutex.Unlock()
+	if r.URL.Path != ce.path {
+		return false
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(ce.statusCode)
+	json.NewEncoder(w).Encode(map[string]string{
+		"errorCode":    "ECHO",
+		"errorMessage": "rejected the credentials " + authorization + " and " + testClientSecret,
+	})
+	return true
+}
+
+func (ce *credentialEcho) secrets() []string {
+	ce.mutex.Lock()
+	defer ce.mutex.Unlock()
+	secrets := []string{
+		testClientSecret,
+		base64.StdEncoding.EncodeToString([]byte(fakeapi.DefaultClientId + ":" + testClientSecret)),
+	}
+	for _, credential := range ce.credentials {
+		if credential != "" {
+			secrets = append(secrets, credential)
+		}
+	}
+	return secrets
+}
+
+// TestCredentialsAreRedacted checks that neither the client secret nor the
+// access tokens, nor the Authorization headers that carry them, appear in the
+// logs or in the errors, even when the API repeats them in its responses.
+func TestCredentialsAreRedacted(t *testing.T) {
+	for name, echo := range map[string]*credentialEcho{
+		"server error":   {path: "/api/beta/networks", statusCode: http.StatusInternalServerError},
+		"unauthorized":   {path: "/api/beta/networks", statusCode: http.StatusUnauthorized},
+		"token rejected": {path: "/api/beta/oauth/token", statusCode: http.StatusUnauthorized},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx, logs := captureLogs(t)
+			api := fakeapi.NewServer()
+			defer api.Close()
+			api.ClientSecret = testClientSecret
+			server := newInterceptedServer(t, api, echo.intercept)
+			c, err := client.NewClient(ctx, server.URL, api.ClientId, api.ClientSecret, client.WithRequestsPerSecond(0), client.WithMaxRetries(1), client.WithRetryMaxWait(10*time.Millisecond))
+			if err == nil {
+				_, err = c.GetNetworks(ctx)
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			output := logs()
+			if !strings.Contains(output, "OpenVPN Cloud API response") || !strings.Contains(output, "ECHO") {
+				t.Fatalf("expected the exchanges with the API to be logged, got %q", output)
+			}
+			for _, secret := range echo.secrets() {
+				if strings.Contains(err.Error(), secret) {
+					t.Errorf("the error includes the credential %q: %v", secret, err)
+				}
+				if strings.Contains(output, secret) {
+					t.Errorf("the logs include the credential %q", secret)
+				}
+			}
+		})
+	}
+}
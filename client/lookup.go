@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// isUnsupportedLookup reports whether err means that the API has no endpoint
+// for a direct lookup or does not accept its filter parameters.
+func isUnsupportedLookup(err error) bool {
+	return hasStatusCode(err, http.StatusBadRequest) ||
+		hasStatusCode(err, http.StatusMethodNotAllowed) ||
+		hasStatusCode(err, http.StatusNotImplemented)
+}
+
+// lookupSupported reports whether the lookup endpoint has not been rejected
+// by the API yet.
+func (c *Client) lookupSupported(endpoint string) bool {
+	_, unsupported := c.unsupportedLookups.Load(endpoint)
+	return !unsupported
+}
+
+// getById unmarshals the object with the given id from the direct endpoint
+// of the collection into out, and reports whether it exists. When the API
+// has no such endpoint, scan is used instead, for this and every later
+// lookup in the collection.
+func (c *Client) getById(ctx context.Context, collection string, id string, out interface{}, scan func() (bool, error)) (bool, error) {
+	endpoint := collection + "/{id}"
+	if c.lookupSupported(endpoint) {
+		path := c.apiPath("%s/%s", collection, url.PathEscape(id))
+		body, err := c.cache.load(ctx, path, func(ctx context.Context) ([]byte, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+path, nil)
+			if err != nil {
+				return nil, err
+			}
+			return c.DoRequest(req)
+		})
+		if err == nil {
+			return true, json.Unmarshal(body, out)
+		}
+		if IsNotFound(err) {
+			return false, nil
+		}
+		if !isUnsupportedLookup(err) {
+			return false, err
+		}
+		c.unsupportedLookups.Store(endpoint, true)
+	}
+	return scan()
+}
+
+// getFiltered unmarshals into out the items of the collection that the API
+// returns for the given filter parameters. The API may ignore the filter, so
+// the caller must still check every item. When the API rejects the filter,
+// the whole collection is read instead, for this and every later lookup with
+// the same parameters.
+func (c *Client) getFiltered(ctx context.Context, collection string, filter url.Values, out interface{}) error {
+	names := make([]string, 0, len(filter))
+	for name := range filter {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return c.getAll(ctx, c.apiPath(collection), out)
+	}
+	endpoint := collection + "?" + strings.Join(names, "&")
+	if c.lookupSupported(endpoint) {
+		err := c.getAll(ctx, c.apiPath("%s?%s", collection, filter.Encode()), out)
+		if err == nil || !isUnsupportedLookup(err) {
+			return err
+		}
+		c.unsupportedLookups.Store(endpoint, true)
+	}
+	return c.getAll(ctx, c.apiPath(collection), out)
+}
@@ -2,9 +2,10 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
+	"net/url"
 )
 
 type Network struct {
@@ -24,88 +25,112 @@ const (
 	InternetAccessLocal          = "LOCAL"
 )
 
-func (c *Client) GetNetworks() ([]Network, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/beta/networks", c.BaseURL), nil)
-	if err != nil {
-		return nil, err
-	}
-	body, err := c.DoRequest(req)
-	if err != nil {
-		return nil, err
-	}
+func (c *Client) GetNetworks(ctx context.Context) ([]Network, error) {
 	var networks []Network
-	err = json.Unmarshal(body, &networks)
+	err := c.getAll(ctx, c.apiPath("networks"), &networks)
 	if err != nil {
 		return nil, err
 	}
+	for i := range networks {
+		c.normalizeNetwork(&networks[i])
+	}
 	return networks, nil
 }
 
-func (c *Client) GetNetworkByName(name string) (*Network, error) {
-	networks, err := c.GetNetworks()
+func (c *Client) GetNetworkByName(ctx context.Context, name string) (*Network, error) {
+	var networks []Network
+	err := c.getFiltered(ctx, "networks", url.Values{"name": {name}}, &networks)
 	if err != nil {
 		return nil, err
 	}
 	for _, n := range networks {
 		if n.Name == name {
+			c.normalizeNetwork(&n)
 			return &n, nil
 		}
 	}
 	return nil, nil
 }
 
-func (c *Client) GetNetworkById(networkId string) (*Network, error) {
-	networks, err := c.GetNetworks()
-	if err != nil {
-		return nil, err
-	}
-	for _, n := range networks {
-		if n.Id == networkId {
-			return &n, nil
+func (c *Client) GetNetworkById(ctx context.Context, networkId string) (*Network, error) {
+	var network Network
+	found, err := c.getById(ctx, "networks", networkId, &network, func() (bool, error) {
+		networks, err := c.GetNetworks(ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, n := range networks {
+			if n.Id == networkId {
+				network = n
+				return true, nil
+			}
 		}
+		return false, nil
+	})
+	if err != nil || !found {
+		return nil, err
 	}
-	return nil, nil
+	c.normalizeNetwork(&network)
+	return &network, nil
 }
 
-func (c *Client) CreateNetwork(network Network) (*Network, error) {
-	networkJson, err := json.Marshal(network)
+func (c *Client) CreateNetwork(ctx context.Context, network Network) (*Network, error) {
+	networkJson, err := c.marshalNetwork(network)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/beta/networks", c.BaseURL), bytes.NewBuffer(networkJson))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL("networks"), bytes.NewBuffer(networkJson))
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.DoRequest(req)
+	var n *Network
+	err = c.createIdempotently(ctx, func() error {
+		body, err := c.doMutation(req, "networks", "connectors")
+		if err != nil {
+			return err
+		}
+		n = &Network{}
+		err = json.Unmarshal(body, n)
+		if err != nil {
+			return err
+		}
+		c.normalizeNetwork(n)
+		return nil
+	}, func() (bool, error) {
+		existing, err := c.GetNetworkByName(ctx, network.Name)
+		n = existing
+		return existing != nil, err
+	})
 	if err != nil {
 		return nil, err
 	}
-	var n Network
-	err = json.Unmarshal(body, &n)
-	if err != nil {
-		return nil, err
+	for _, r := range n.Routes {
+		c.routes.add(r.Id, n.Id)
 	}
-	return &n, nil
+	return n, nil
 }
 
-func (c *Client) UpdateNetwork(network Network) error {
-	networkJson, err := json.Marshal(network)
+func (c *Client) UpdateNetwork(ctx context.Context, network Network) error {
+	networkJson, err := c.marshalNetwork(network)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/beta/networks/%s", c.BaseURL, network.Id), bytes.NewBuffer(networkJson))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.apiURL("networks/%s", network.Id), bytes.NewBuffer(networkJson))
 	if err != nil {
 		return err
 	}
-	_, err = c.DoRequest(req)
+	_, err = c.doMutation(req, "networks")
 	return err
 }
 
-func (c *Client) DeleteNetwork(networkId string) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/beta/networks/%s", c.BaseURL, networkId), nil)
+func (c *Client) DeleteNetwork(ctx context.Context, networkId string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.apiURL("networks/%s", networkId), nil)
 	if err != nil {
 		return err
 	}
-	_, err = c.DoRequest(req)
+	_, err = c.doMutation(req, "networks", "connectors")
+	if err == nil || IsNotFound(err) {
+		c.routes.removeNetwork(networkId)
+	}
 	return err
 }
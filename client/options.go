@@ -0,0 +1,133 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const DefaultRequestTimeout = 10 * time.Second
+
+// Option configures a Client created with NewClient.
+type Option func(*Client) error
+
+// WithRequestTimeout sets the time limit for each HTTP request, including the
+// time to read the response body.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		c.HTTPClient.Timeout = timeout
+		return nil
+	}
+}
+
+// WithRoundTripper wraps the transport of the client, for instance to record
+// or replay its requests in tests. The transport settings of the other options
+// still apply to the wrapped transport.
+func WithRoundTripper(wrap func(http.RoundTripper) http.RoundTripper) Option {
+	return func(c *Client) error {
+		c.HTTPClient.Transport = wrap(c.HTTPClient.Transport)
+		return nil
+	}
+}
+
+// WithReadOnly makes the client refuse every request that could change an
+// object, while reads keep working.
+func WithReadOnly(readOnly bool) Option {
+	return func(c *Client) error {
+		c.ReadOnly = readOnly
+		return nil
+	}
+}
+
+// WithProxyURL sends every request through the given proxy instead of the one
+// configured in the environment.
+func WithProxyURL(proxyURL string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL %q: %w", proxyURL, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid proxy URL %q: scheme and host are required", proxyURL)
+		}
+		c.transport.Proxy = http.ProxyURL(u)
+		return nil
+	}
+}
+
+// WithCABundleFile trusts the PEM encoded certificates in the given file in
+// addition to the system certificate pool.
+func WithCABundleFile(path string) Option {
+	return func(c *Client) error {
+		pem, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("unable to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no PEM encoded certificates found in CA bundle %s", path)
+		}
+		c.tlsConfig().RootCAs = pool
+		return nil
+	}
+}
+
+// WithClientCertificate presents the given certificate and key for mutual TLS
+// authentication.
+func WithClientCertificate(certFile, keyFile string) Option {
+	return func(c *Client) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("unable to load client certificate: %w", err)
+		}
+		c.tlsConfig().Certificates = []tls.Certificate{cert}
+		return nil
+	}
+}
+
+// WithInsecureSkipVerify disables the verification of the server certificate.
+// This should only ever be used for testing.
+func WithInsecureSkipVerify(skip bool) Option {
+	return func(c *Client) error {
+		c.tlsConfig().InsecureSkipVerify = skip
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		c.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithMaxRetries sets how many times a failed request is retried.
+func WithMaxRetries(maxRetries int) Option {
+	return func(c *Client) error {
+		c.MaxRetries = maxRetries
+		return nil
+	}
+}
+
+// WithRetryMaxWait caps the time to wait between two retries.
+func WithRetryMaxWait(maxWait time.Duration) Option {
+	return func(c *Client) error {
+		c.RetryMaxWait = maxWait
+		return nil
+	}
+}
+
+func (c *Client) tlsConfig() *tls.Config {
+	if c.transport.TLSClientConfig == nil {
+		c.transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return c.transport.TLSClientConfig
+}
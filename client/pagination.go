@@ -0,0 +1,104 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const DefaultPageSize = 100
+
+// page is the envelope returned by paginated list endpoints. The content and
+// at least one of last and totalPages are required, so that a JSON object that
+// is not a page is not read as an empty list.
+type page struct {
+	Content    *[]json.RawMessage `json:"content"`
+	Last       *bool              `json:"last"`
+	TotalPages *int               `json:"totalPages"`
+}
+
+// WithPageSize sets how many items are requested per page from paginated
+// list endpoints.
+func WithPageSize(pageSize int) Option {
+	return func(c *Client) error {
+		if pageSize < 1 {
+			return fmt.Errorf("invalid page size %d: must be at least 1", pageSize)
+		}
+		c.PageSize = pageSize
+		return nil
+	}
+}
+
+// getAll unmarshals every item of the list endpoint at path into out, which
+// must be a pointer to a slice. Items are read from the snapshot of the
+// endpoint if there is a fresh one.
+func (c *Client) getAll(ctx context.Context, path string, out interface{}) error {
+	items, err := c.cache.load(ctx, path, func(ctx context.Context) ([]byte, error) {
+		return c.fetchAll(ctx, path)
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(items, out)
+}
+
+// fetchAll fetches every page of the list endpoint at path and returns all the
+// items as a JSON array. Pages are read until one is marked as the last or is
+// empty. The total number of pages is only used by envelopes that do not mark
+// the last page. Endpoints that return a bare JSON array instead of a page
+// envelope are read in one request.
+func (c *Client) fetchAll(ctx context.Context, path string) ([]byte, error) {
+	var items []json.RawMessage
+	for pageNumber := 0; ; pageNumber++ {
+		u, err := url.Parse(c.BaseURL + path)
+		if err != nil {
+			return nil, err
+		}
+		query := u.Query()
+		query.Set("page", strconv.Itoa(pageNumber))
+		query.Set("size", strconv.Itoa(c.PageSize))
+		u.RawQuery = query.Encode()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		body, err := c.DoRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.TrimSpace(body)
+		if len(body) > 0 && body[0] == '[' {
+			if pageNumber == 0 {
+				return body, nil
+			}
+			return nil, fmt.Errorf("unexpected unpaginated response for page %d of %s", pageNumber, path)
+		}
+		var p page
+		err = json.Unmarshal(body, &p)
+		if err != nil {
+			return nil, err
+		}
+		if p.Content == nil || (p.Last == nil && p.TotalPages == nil) {
+			return nil, fmt.Errorf("%w: the response for page %d of %s is not a page of items", ErrNotOpenVPNCloudAPI, pageNumber, path)
+		}
+		items = append(items, *p.Content...)
+		if len(*p.Content) == 0 {
+			break
+		}
+		if p.Last != nil {
+			if *p.Last {
+				break
+			}
+		} else if pageNumber+1 >= *p.TotalPages {
+			break
+		}
+	}
+	if items == nil {
+		items = []json.RawMessage{}
+	}
+	return json.Marshal(items)
+}
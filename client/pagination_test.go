@@ -0,0 +1,163 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client/fakeapi"
+)
+
+func TestGetAllReadsEveryPage(t *testing.T) {
+	ctx := context.Background()
+	api := fakeapi.NewServer()
+	defer api.Close()
+	for i := 0; i < 5; i++ {
+		_, err := api.Backend.CreateNetwork(ctx, client.Network{Name: fmt.Sprintf("tf-page-%d", i), InternetAccess: client.InternetAccessLocal})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	var pages int32
+	server := newInterceptedServer(t, api, func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method == http.MethodGet && r.URL.Path == "/api/beta/networks" {
+			atomic.AddInt32(&pages, 1)
+		}
+		return false
+	})
+	c, err := client.NewClient(ctx, server.URL, api.ClientId, api.ClientSecret, client.WithRequestsPerSecond(0), client.WithPageSize(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	networks, err := c.GetNetworks(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(networks) != 5 {
+		t.Errorf("expected 5 networks, got %d", len(networks))
+	}
+	if pages != 3 {
+		t.Errorf("expected 3 pages to be read, got %d", pages)
+	}
+}
+
+// TestGetAllPageEnvelopes checks where the pagination stops for envelopes that
+// leave out the total number of pages or the last page, or report a wrong
+// total.
+func TestGetAllPageEnvelopes(t *testing.T) {
+	regions := []client.VpnRegion{{Id: "region-0"}, {Id: "region-1"}, {Id: "region-2"}, {Id: "region-3"}, {Id: "region-4"}}
+	for name, tc := range map[string]struct {
+		envelope func(content []client.VpnRegion, last bool) map[string]interface{}
+		pages    int32
+	}{
+		"without total pages": {
+			envelope: func(content []client.VpnRegion, last bool) map[string]interface{} {
+				return map[string]interface{}{"content": content, "last": last}
+			},
+			pages: 3,
+		},
+		"without last": {
+			envelope: func(content []client.VpnRegion, last bool) map[string]interface{} {
+				return map[string]interface{}{"content": content, "totalPages": 3}
+			},
+			pages: 3,
+		},
+		"with a wrong total": {
+			envelope: func(content []client.VpnRegion, last bool) map[string]interface{} {
+				return map[string]interface{}{"content": content, "last": last, "totalPages": 1}
+			},
+			pages: 3,
+		},
+		"never last": {
+			envelope: func(content []client.VpnRegion, last bool) map[string]interface{} {
+				return map[string]interface{}{"content": content, "last": false}
+			},
+			pages: 4,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			api := fakeapi.NewServer()
+			defer api.Close()
+			var pages int32
+			server := newInterceptedServer(t, api, func(w http.ResponseWriter, r *http.Request) bool {
+				if r.URL.Path != "/api/beta/regions" {
+					return false
+				}
+				atomic.AddInt32(&pages, 1)
+				pageNumber, _ := strconv.Atoi(r.URL.Query().Get("page"))
+				size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+				content := []client.VpnRegion{}
+				if start := pageNumber * size; start < len(regions) {
+					end := start + size
+					if end > len(regions) {
+						end = len(regions)
+					}
+					content = regions[start:end]
+				}
+				json.NewEncoder(w).Encode(tc.envelope(content, (pageNumber+1)*size >= len(regions)))
+				return true
+			})
+			c, err := client.NewClient(ctx, server.URL, api.ClientId, api.ClientSecret, client.WithRequestsPerSecond(0), client.WithPageSize(2))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range regions {
+				region, err := c.GetVpnRegion(ctx, r.Id)
+				if err != nil || region == nil {
+					t.Errorf("GetVpnRegion(%q) returned %+v, %v", r.Id, region, err)
+				}
+			}
+			if pages != tc.pages {
+				t.Errorf("expected %d pages to be read, got %d", tc.pages, pages)
+			}
+		})
+	}
+}
+
+// TestGetAllRejectsOtherJSON checks that a JSON response that is neither a
+// list nor a page of items is an error rather than an empty list, so that Ping
+// does not accept a server that is not the API, while a page that only reports
+// the total number of pages is accepted.
+func TestGetAllRejectsOtherJSON(t *testing.T) {
+	for name, tc := range map[string]struct {
+		body     string
+		accepted bool
+	}{
+		"object":                  {body: `{"status":"ok"}`},
+		"null content":            {body: `{"content":null,"last":true}`},
+		"no last nor total pages": {body: `{"content":[]}`},
+		"total pages only":        {body: `{"content":[],"totalPages":0}`, accepted: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			api := fakeapi.NewServer()
+			defer api.Close()
+			server := newInterceptedServer(t, api, func(w http.ResponseWriter, r *http.Request) bool {
+				if r.URL.Path != "/api/beta/regions" {
+					return false
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, tc.body)
+				return true
+			})
+			c, err := client.NewClient(ctx, server.URL, api.ClientId, api.ClientSecret, client.WithRequestsPerSecond(0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = c.Ping(ctx)
+			if tc.accepted && err != nil {
+				t.Errorf("expected Ping to succeed, got %v", err)
+			}
+			if !tc.accepted && !errors.Is(err, client.ErrNotOpenVPNCloudAPI) {
+				t.Errorf("expected Ping to fail with ErrNotOpenVPNCloudAPI, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	DefaultMaxRetries   = 3
+	DefaultRetryMaxWait = 30 * time.Second
+
+	retryBaseWait = 500 * time.Millisecond
+)
+
+// ambiguousError wraps the failure of a non-idempotent request after which
+// it is unknown whether the server processed it.
+type ambiguousError struct {
+	err error
+}
+
+func (e *ambiguousError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ambiguousError) Unwrap() error {
+	return e.err
+}
+
+func isAmbiguous(err error) bool {
+	var ambiguous *ambiguousError
+	return errors.As(err, &ambiguous)
+}
+
+// isSafe reports whether a request with the given method only reads.
+func isSafe(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func isIdempotent(method string) bool {
+	return method != http.MethodPost && method != http.MethodPatch
+}
+
+// shouldRetry reports whether a request that ended with the given status code
+// or transport error can be sent again. A 429 means the request was rejected
+// before it was processed, so it is always safe to retry. Server errors and
+// transport errors are only retried for idempotent methods, unless the
+// connection could not even be established.
+func shouldRetry(method string, statusCode int, err error) bool {
+	if err != nil {
+		return isTransientNetworkError(err) && (isIdempotent(method) || isDialError(err))
+	}
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if statusCode >= 500 && statusCode != http.StatusNotImplemented {
+		return isIdempotent(method)
+	}
+	return false
+}
+
+func isTransientNetworkError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+}
+
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// retryAfter parses the Retry-After header, which can either be a number of
+// seconds or an HTTP date.
+func retryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// retryWait returns an exponential backoff with jitter for the given attempt,
+// extended to honour the server's Retry-After and capped at RetryMaxWait.
+func (c *Client) retryWait(attempt int, after time.Duration) time.Duration {
+	wait := c.RetryMaxWait
+	if attempt < 16 && retryBaseWait<<attempt < wait {
+		wait = retryBaseWait << attempt
+	}
+	wait = wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+	if after > wait {
+		wait = after
+	}
+	if wait > c.RetryMaxWait {
+		wait = c.RetryMaxWait
+	}
+	return wait
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// createIdempotently runs a non-idempotent create request. When it fails in a
+// way that leaves it unknown whether the object was created, lookup is used to
+// check for it before the request is sent again, so that a retry can never
+// create a duplicate.
+func (c *Client) createIdempotently(ctx context.Context, create func() error, lookup func() (bool, error)) error {
+	for attempt := 0; ; attempt++ {
+		err := create()
+		if err == nil || !isAmbiguous(err) || attempt >= c.MaxRetries {
+			return err
+		}
+		found, lookupErr := lookup()
+		if lookupErr != nil {
+			return err
+		}
+		if found {
+			tflog.Debug(ctx, "Create request failed, but the object was created", "error", c.redact(err.Error()))
+			return nil
+		}
+		wait := c.retryWait(attempt, 0)
+		tflog.Debug(ctx, "Create request failed, retrying", "error", c.redact(err.Error()), "wait_ms", wait.Milliseconds())
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client/fakeapi"
+)
+
+// fault makes the fake API fail the first times requests with the given
+// method and path. A processed fault lets the fake API handle the request
+// before the failure is returned, like a response lost on its way back.
+type fault struct {
+	method     string
+	path       string
+	times      int32
+	statusCode int
+	retryAfter string
+	processed  bool
+
+	seen int32
+}
+
+func (f *fault) intercept(api *fakeapi.Server) func(w http.ResponseWriter, r *http.Request) bool {
+	return func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method != f.method || r.URL.Path != f.path {
+			return false
+		}
+		if atomic.AddInt32(&f.seen, 1) > f.times {
+			return false
+		}
+		if f.processed {
+			api.ServeHTTP(httptest.NewRecorder(), r)
+		}
+		if f.retryAfter != "" {
+			w.Header().Set("Retry-After", f.retryAfter)
+		}
+		w.WriteHeader(f.statusCode)
+		return true
+	}
+}
+
+// requests returns how many requests matched the fault, including the ones
+// that were let through.
+func (f *fault) requests() int32 {
+	return atomic.LoadInt32(&f.seen)
+}
+
+func newFaultyClient(t *testing.T, api *fakeapi.Server, f *fault, opts ...client.Option) *client.Client {
+	server := newInterceptedServer(t, api, f.intercept(api))
+	opts = append([]client.Option{client.WithRequestsPerSecond(0), client.WithCacheTTL(0)}, opts...)
+	c, err := client.NewClient(context.Background(), server.URL, api.ClientId, api.ClientSecret, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	// An HTTP date has a resolution of one second, so the date is set far
+	// enough ahead to still be a second away once truncated.
+	for name, retryAfter := range map[string]func() string{
+		"seconds": func() string { return "1" },
+		"date":    func() string { return time.Now().Add(2 * time.Second).UTC().Format(http.TimeFormat) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := fakeapi.NewServer()
+			defer api.Close()
+			f := &fault{method: http.MethodGet, path: "/api/beta/networks", times: 1, statusCode: http.StatusTooManyRequests, retryAfter: retryAfter()}
+			c := newFaultyClient(t, api, f)
+			start := time.Now()
+			_, err := c.GetNetworks(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+				t.Errorf("expected the retry to wait for the Retry-After header, it was sent after %s", elapsed)
+			}
+			if f.requests() != 2 {
+				t.Errorf("expected 2 requests, got %d", f.requests())
+			}
+		})
+	}
+}
+
+// TestRetryWaitIsCapped checks that neither the backoff nor a long Retry-After
+// make the client wait longer than RetryMaxWait between two retries.
+func TestRetryWaitIsCapped(t *testing.T) {
+	api := fakeapi.NewServer()
+	defer api.Close()
+	f := &fault{method: http.MethodGet, path: "/api/beta/networks", times: 3, statusCode: http.StatusServiceUnavailable, retryAfter: "30"}
+	c := newFaultyClient(t, api, f, client.WithRetryMaxWait(50*time.Millisecond))
+	start := time.Now()
+	_, err := c.GetNetworks(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected the retries to wait at most 50ms each, they took %s", elapsed)
+	}
+	if f.requests() != 4 {
+		t.Errorf("expected 4 requests, got %d", f.requests())
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	api := fakeapi.NewServer()
+	defer api.Close()
+	f := &fault{method: http.MethodGet, path: "/api/beta/networks", times: 10, statusCode: http.StatusBadGateway}
+	c := newFaultyClient(t, api, f, client.WithMaxRetries(2), client.WithRetryMaxWait(10*time.Millisecond))
+	_, err := c.GetNetworks(context.Background())
+	if !hasStatusCode(err, http.StatusBadGateway) {
+		t.Errorf("expected a 502 error, got %v", err)
+	}
+	if f.requests() != 3 {
+		t.Errorf("expected 3 requests, got %d", f.requests())
+	}
+}
+
+// TestNoRetryOfNonIdempotentRequests checks that a POST that failed with a
+// server error is not sent again by DoRequest, since the server may have
+// processed it, while a rate limited POST is.
+func TestNoRetryOfNonIdempotentRequests(t *testing.T) {
+	for name, tc := range map[string]struct {
+		statusCode int
+		requests   int32
+	}{
+		"server error": {statusCode: http.StatusServiceUnavailable, requests: 1},
+		"rate limited": {statusCode: http.StatusTooManyRequests, requests: 2},
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := fakeapi.NewServer()
+			defer api.Close()
+			f := &fault{method: http.MethodPost, path: "/api/beta/dns-records", times: 1, statusCode: tc.statusCode}
+			c := newFaultyClient(t, api, f, client.WithRetryMaxWait(10*time.Millisecond))
+			req, err := http.NewRequest(http.MethodPost, c.BaseURL+"/api/beta/dns-records", bytes.NewBufferString(`{"domain":"retry.example.com","ipv4Addresses":["10.0.0.1"]}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.DoRequest(req)
+			if f.requests() != tc.requests {
+				t.Errorf("expected %d requests, got %d", tc.requests, f.requests())
+			}
+		})
+	}
+}
+
+// TestCreateAfterLostResponse checks that a create whose response was lost is
+// found by its lookup instead of being sent again.
+func TestCreateAfterLostResponse(t *testing.T) {
+	ctx := context.Background()
+	api := fakeapi.NewServer()
+	defer api.Close()
+	f := &fault{method: http.MethodPost, path: "/api/beta/networks", times: 1, statusCode: http.StatusBadGateway, processed: true}
+	c := newFaultyClient(t, api, f, client.WithRetryMaxWait(10*time.Millisecond))
+	network, err := c.CreateNetwork(ctx, client.Network{Name: "tf-lost-response", InternetAccess: client.InternetAccessLocal})
+	if err != nil {
+		t.Fatal(err)
+	}
+	networks, _ := api.Backend.GetNetworks(ctx)
+	if len(networks) != 1 || network == nil || networks[0].Id != network.Id {
+		t.Errorf("expected a single network to be created and returned, got %+v and %+v", networks, network)
+	}
+	if f.requests() != 1 {
+		t.Errorf("expected a single POST, got %d", f.requests())
+	}
+}
+
+// TestCreateDeviceAfterLostResponse checks that a device whose response was
+// lost is found by name among the devices of its user instead of being sent
+// again.
+func TestCreateDeviceAfterLostResponse(t *testing.T) {
+	ctx := context.Background()
+	api := fakeapi.NewServer()
+	defer api.Close()
+	user, err := api.Backend.CreateUser(ctx, client.User{Username: "tf-lost-device", Email: "tf-lost-device@example.com", Role: client.UserRoleMember})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fault{method: http.MethodPost, path: "/api/beta/users/" + user.Id + "/devices", times: 1, statusCode: http.StatusBadGateway, processed: true}
+	c := newFaultyClient(t, api, f, client.WithRetryMaxWait(10*time.Millisecond))
+	device, err := c.CreateDevice(ctx, user.Id, client.Device{Name: "tf-lost-device"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, _ = api.Backend.GetUserById(ctx, user.Id)
+	if len(user.Devices) != 1 || device == nil || user.Devices[0].Id != device.Id {
+		t.Errorf("expected a single device to be created and returned, got %+v and %+v", user.Devices, device)
+	}
+	if f.requests() != 1 {
+		t.Errorf("expected a single POST, got %d", f.requests())
+	}
+}
+
+// TestCreateLocationContextAfterLostResponse checks that a location context
+// whose response was lost is looked up by name rather than by reading every
+// location context.
+func TestCreateLocationContextAfterLostResponse(t *testing.T) {
+	ctx := context.Background()
+	api := fakeapi.NewServer()
+	defer api.Close()
+	f := &fault{method: http.MethodPost, path: "/api/beta/location-contexts", times: 1, statusCode: http.StatusBadGateway, processed: true}
+	var unfiltered int32
+	intercept := f.intercept(api)
+	server := newInterceptedServer(t, api, func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Method == http.MethodGet && r.URL.Path == "/api/beta/location-contexts" && r.URL.Query().Get("name") == "" {
+			atomic.AddInt32(&unfiltered, 1)
+		}
+		return intercept(w, r)
+	})
+	c, err := client.NewClient(ctx, server.URL, api.ClientId, api.ClientSecret, client.WithRequestsPerSecond(0), client.WithCacheTTL(0), client.WithRetryMaxWait(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	locationContext, err := c.CreateLocationContext(ctx, client.LocationContext{Name: "tf-lost-location-context", DefaultCheck: client.DefaultCheck{Allowed: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	locationContexts, _ := api.Backend.GetLocationContexts(ctx)
+	if len(locationContexts) != 1 || locationContext == nil || locationContexts[0].Id != locationContext.Id {
+		t.Errorf("expected a single location context to be created and returned, got %+v and %+v", locationContexts, locationContext)
+	}
+	if f.requests() != 1 {
+		t.Errorf("expected a single POST, got %d", f.requests())
+	}
+	if atomic.LoadInt32(&unfiltered) != 0 {
+		t.Errorf("expected the location context to be looked up by name, but every location context was read %d times", unfiltered)
+	}
+}
+
+// TestCreateAfterUnprocessedFailure checks that a create that failed before
+// the server processed it is sent again once its lookup found nothing.
+func TestCreateAfterUnprocessedFailure(t *testing.T) {
+	ctx := context.Background()
+	api := fakeapi.NewServer()
+	defer api.Close()
+	f := &fault{method: http.MethodPost, path: "/api/beta/networks", times: 1, statusCode: http.StatusBadGateway}
+	c := newFaultyClient(t, api, f, client.WithRetryMaxWait(10*time.Millisecond))
+	network, err := c.CreateNetwork(ctx, client.Network{Name: "tf-unprocessed", InternetAccess: client.InternetAccessLocal})
+	if err != nil {
+		t.Fatal(err)
+	}
+	networks, _ := api.Backend.GetNetworks(ctx)
+	if len(networks) != 1 || networks[0].Id != network.Id {
+		t.Errorf("expected a single network to be created, got %+v", networks)
+	}
+	if f.requests() != 2 {
+		t.Errorf("expected 2 POSTs, got %d", f.requests())
+	}
+}
+
+// TestRetriedDeleteReportsNotFound checks that a DELETE retried after its
+// response was lost reports the object as not found, which callers treat as
+// deleted.
+func TestRetriedDeleteReportsNotFound(t *testing.T) {
+	ctx := context.Background()
+	api := fakeapi.NewServer()
+	defer api.Close()
+	network, err := api.Backend.CreateNetwork(ctx, client.Network{Name: "tf-retried-delete", InternetAccess: client.InternetAccessLocal})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fault{method: http.MethodDelete, path: "/api/beta/networks/" + network.Id, times: 1, statusCode: http.StatusBadGateway, processed: true}
+	c := newFaultyClient(t, api, f, client.WithRetryMaxWait(10*time.Millisecond))
+	err = c.DeleteNetwork(ctx, network.Id)
+	if !client.IsNotFound(err) {
+		t.Errorf("expected a 404 error, got %v", err)
+	}
+	if f.requests() != 2 {
+		t.Errorf("expected 2 DELETEs, got %d", f.requests())
+	}
+}
+
+func hasStatusCode(err error, statusCode int) bool {
+	var apiErr *client.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
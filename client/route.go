@@ -2,8 +2,8 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 )
 
@@ -22,55 +22,74 @@ const (
 	RouteTypeDomain = "DOMAIN"
 )
 
-func (c *Client) CreateRoute(networkId string, route Route) (*Route, error) {
-	routeJson, err := json.Marshal(route)
+func (c *Client) CreateRoute(ctx context.Context, networkId string, route Route) (*Route, error) {
+	routeJson, err := c.marshalRoute(route)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/beta/networks/%s/routes", c.BaseURL, networkId), bytes.NewBuffer(routeJson))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL("networks/%s/routes", networkId), bytes.NewBuffer(routeJson))
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.DoRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	var r Route
-	err = json.Unmarshal(body, &r)
+	var r *Route
+	err = c.createIdempotently(ctx, func() error {
+		body, err := c.doMutation(req, "networks")
+		if err != nil {
+			return err
+		}
+		r = &Route{}
+		err = json.Unmarshal(body, r)
+		if err != nil {
+			return err
+		}
+		c.normalizeRoute(r)
+		return nil
+	}, func() (bool, error) {
+		routes, err := c.GetRoutes(ctx, networkId)
+		if err != nil {
+			return false, err
+		}
+		for _, existing := range routes {
+			if existing.Type == route.Type && (existing.Subnet == route.Value || existing.Domain == route.Value) {
+				r = &existing
+				return true, nil
+			}
+		}
+		return false, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	return &r, nil
+	c.routes.add(r.Id, networkId)
+	return r, nil
 }
 
-func (c *Client) DeleteRoute(networkId string, routeId string) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/beta/networks/%s/routes/%s", c.BaseURL, networkId, routeId), nil)
+func (c *Client) DeleteRoute(ctx context.Context, networkId string, routeId string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.apiURL("networks/%s/routes/%s", networkId, routeId), nil)
 	if err != nil {
 		return err
 	}
-	_, err = c.DoRequest(req)
+	_, err = c.doMutation(req, "networks")
+	if err == nil || IsNotFound(err) {
+		c.routes.remove(routeId)
+	}
 	return err
 }
 
-func (c *Client) GetRoutes(networkId string) ([]Route, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/beta/networks/%s/routes", c.BaseURL, networkId), nil)
-	if err != nil {
-		return nil, err
-	}
-	body, err := c.DoRequest(req)
-	if err != nil {
-		return nil, err
-	}
+func (c *Client) GetRoutes(ctx context.Context, networkId string) ([]Route, error) {
 	var routes []Route
-	err = json.Unmarshal(body, &routes)
+	err := c.getAll(ctx, c.apiPath("networks/%s/routes", networkId), &routes)
 	if err != nil {
 		return nil, err
 	}
+	for i := range routes {
+		c.normalizeRoute(&routes[i])
+	}
 	return routes, nil
 }
 
-func (c *Client) GetNetworkRoute(networkId string, routeId string) (*Route, error) {
-	routes, err := c.GetRoutes(networkId)
+func (c *Client) GetNetworkRoute(ctx context.Context, networkId string, routeId string) (*Route, error) {
+	routes, err := c.GetRoutes(ctx, networkId)
 	if err != nil {
 		return nil, err
 	}
@@ -82,33 +101,37 @@ func (c *Client) GetNetworkRoute(networkId string, routeId string) (*Route, erro
 	return nil, nil
 }
 
-func (c *Client) GetRouteById(routeId string) (*Route, error) {
-	networks, err := c.GetNetworks()
+func (c *Client) GetRouteById(ctx context.Context, routeId string) (*Route, error) {
+	networkId, err := c.networkOfRoute(ctx, routeId)
+	if err != nil || networkId == "" {
+		return nil, err
+	}
+	r, err := c.GetNetworkRoute(ctx, networkId, routeId)
+	if IsNotFound(err) {
+		// The network was deleted along with its routes
+		c.routes.removeNetwork(networkId)
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
-	for _, n := range networks {
-		r, err := c.GetNetworkRoute(n.Id, routeId)
-		if err != nil {
-			return nil, err
-		}
-		if r != nil {
-			r.NetworkItemId = n.Id
-			return r, nil
-		}
+	if r == nil {
+		c.routes.remove(routeId)
+		return nil, nil
 	}
-	return nil, nil
+	r.NetworkItemId = networkId
+	return r, nil
 }
 
-func (c *Client) UpdateRoute(networkId string, route Route) error {
-	routeJson, err := json.Marshal(route)
+func (c *Client) UpdateRoute(ctx context.Context, networkId string, route Route) error {
+	routeJson, err := c.marshalRoute(route)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/beta/networks/%s/routes/%s", c.BaseURL, networkId, route.Id), bytes.NewBuffer(routeJson))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.apiURL("networks/%s/routes/%s", networkId, route.Id), bytes.NewBuffer(routeJson))
 	if err != nil {
 		return err
 	}
-	_, err = c.DoRequest(req)
+	_, err = c.doMutation(req, "networks")
 	return err
 }
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// routeIndexParallelism is how many networks have their routes listed at the
+// same time while the route index is rebuilt.
+const routeIndexParallelism = 8
+
+// routeIndex maps the id of every known route to the id of the network it
+// belongs to, so that a route can be read without scanning every network.
+// It is rebuilt by the lookups that miss, and kept in sync by the route and
+// network mutations of the client.
+type routeIndex struct {
+	calls    sharedCalls
+	mutex    sync.Mutex
+	networks map[string]string
+	// added holds the routes created while the index is being rebuilt, which
+	// the rebuild may have missed.
+	added map[string]string
+}
+
+func newRouteIndex() *routeIndex {
+	return &routeIndex{
+		networks: map[string]string{},
+	}
+}
+
+func (ri *routeIndex) lookup(routeId string) (string, bool) {
+	ri.mutex.Lock()
+	defer ri.mutex.Unlock()
+	networkId, ok := ri.networks[routeId]
+	return networkId, ok
+}
+
+func (ri *routeIndex) add(routeId string, networkId string) {
+	ri.mutex.Lock()
+	defer ri.mutex.Unlock()
+	ri.networks[routeId] = networkId
+	if ri.added != nil {
+		ri.added[routeId] = networkId
+	}
+}
+
+func (ri *routeIndex) remove(routeId string) {
+	ri.mutex.Lock()
+	defer ri.mutex.Unlock()
+	delete(ri.networks, routeId)
+}
+
+func (ri *routeIndex) removeNetwork(networkId string) {
+	ri.mutex.Lock()
+	defer ri.mutex.Unlock()
+	for routeId, n := range ri.networks {
+		if n == networkId {
+			delete(ri.networks, routeId)
+		}
+	}
+}
+
+// networkOfRoute returns the id of the network the route belongs to, or an
+// empty string if there is no such route. A route the index does not know may
+// have been created by someone else, so the index is rebuilt from fresh lists
+// of the networks and their routes before the route is reported missing.
+// Lookups that miss at the same time share a single rebuild, which is
+// cancelled once all of them have given up.
+func (c *Client) networkOfRoute(ctx context.Context, routeId string) (string, error) {
+	networkId, ok := c.routes.lookup(routeId)
+	if ok {
+		return networkId, nil
+	}
+	c.cache.invalidate(c.apiPath("networks"))
+	_, err := c.routes.calls.do(ctx, "rebuild", func(ctx context.Context) (interface{}, error) {
+		return nil, c.rebuildRouteIndex(ctx)
+	})
+	if err != nil {
+		return "", err
+	}
+	networkId, _ = c.routes.lookup(routeId)
+	return networkId, nil
+}
+
+// rebuildRouteIndex lists the routes of every network, routeIndexParallelism
+// networks at a time, and replaces the content of the index with them.
+func (c *Client) rebuildRouteIndex(ctx context.Context) error {
+	c.routes.mutex.Lock()
+	c.routes.added = map[string]string{}
+	c.routes.mutex.Unlock()
+	defer func() {
+		c.routes.mutex.Lock()
+		c.routes.added = nil
+		c.routes.mutex.Unlock()
+	}()
+	networks, err := c.GetNetworks(ctx)
+	if err != nil {
+		return err
+	}
+	var mutex sync.Mutex
+	index := map[string]string{}
+	g, ctx := errgroup.WithContext(ctx)
+	slots := make(chan struct{}, routeIndexParallelism)
+	for _, n := range networks {
+		networkId := n.Id
+		g.Go(func() error {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-slots }()
+			routes, err := c.GetRoutes(ctx, networkId)
+			if IsNotFound(err) {
+				// The network was deleted while the index was being rebuilt
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			for _, r := range routes {
+				index[r.Id] = networkId
+			}
+			return nil
+		})
+	}
+	err = g.Wait()
+	if err != nil {
+		return err
+	}
+	c.routes.mutex.Lock()
+	defer c.routes.mutex.Unlock()
+	for routeId, networkId := range c.routes.added {
+		index[routeId] = networkId
+	}
+	c.routes.networks = index
+	return nil
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
+)
+
+const (
+	DefaultRequestsPerSecond     = 10
+	DefaultMaxConcurrentRequests = 5
+)
+
+// WithRequestsPerSecond limits the rate at which requests are sent to the API.
+// A rate of 0 removes the limit.
+func WithRequestsPerSecond(requestsPerSecond float64) Option {
+	return func(c *Client) error {
+		if requestsPerSecond < 0 {
+			return fmt.Errorf("invalid request rate %g: must not be negative", requestsPerSecond)
+		}
+		if requestsPerSecond == 0 {
+			c.limiter = nil
+			return nil
+		}
+		burst := int(math.Ceil(requestsPerSecond))
+		c.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+		return nil
+	}
+}
+
+// WithMaxConcurrentRequests limits how many requests can be in flight at the
+// same time. A limit of 0 removes the limit.
+func WithMaxConcurrentRequests(maxConcurrentRequests int) Option {
+	return func(c *Client) error {
+		if maxConcurrentRequests < 0 {
+			return fmt.Errorf("invalid maximum of concurrent requests %d: must not be negative", maxConcurrentRequests)
+		}
+		if maxConcurrentRequests == 0 {
+			c.slots = nil
+			return nil
+		}
+		c.slots = make(chan struct{}, maxConcurrentRequests)
+		return nil
+	}
+}
+
+// throttle blocks until the request is allowed by both the rate limiter and
+// the concurrency limit, and returns the function that releases its slot once
+// the response has been read.
+func (c *Client) throttle(req *http.Request) (func(), error) {
+	ctx := req.Context()
+	start := time.Now()
+	if c.limiter != nil {
+		err := c.limiter.Wait(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	limited := time.Since(start)
+	release := func() {}
+	if c.slots != nil {
+		select {
+		case c.slots <- struct{}{}:
+			release = func() { <-c.slots }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	queued := time.Since(start) - limited
+	if limited+queued >= time.Millisecond {
+		tflog.Debug(ctx, "Throttled OpenVPN Cloud API request",
+			"method", req.Method,
+			"path", req.URL.Path,
+			"rate_limit_wait_ms", limited.Milliseconds(),
+			"concurrency_wait_ms", queued.Milliseconds(),
+		)
+	}
+	return release, nil
+}
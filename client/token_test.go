@@ -0,0 +1,174 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client/fakeapi"
+)
+
+// tokenCounter counts the token requests sent to the fake API, and the other
+// requests it answered with a 401.
+type tokenCounter struct {
+	tokens       int32
+	unauthorized int32
+}
+
+func (tc *tokenCounter) counts() (int32, int32) {
+	return atomic.LoadInt32(&tc.tokens), atomic.LoadInt32(&tc.unauthorized)
+}
+
+func newTokenCountingClient(t *testing.T, api *fakeapi.Server, counter *tokenCounter) *client.Client {
+	server := newInterceptedServer(t, api, func(w http.ResponseWriter, r *http.Request) bool {
+		if strings.HasSuffix(r.URL.Path, "/oauth/token") {
+			atomic.AddInt32(&counter.tokens, 1)
+			return false
+		}
+		recorder := &statusRecorder{ResponseWriter: w}
+		api.ServeHTTP(recorder, r)
+		if recorder.statusCode == http.StatusUnauthorized {
+			atomic.AddInt32(&counter.unauthorized, 1)
+		}
+		return true
+	})
+	c, err := client.NewClient(context.Background(), server.URL, api.ClientId, api.ClientSecret, client.WithRequestsPerSecond(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// statusRecorder remembers the status code written to the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (sr *statusRecorder) WriteHeader(statusCode int) {
+	sr.statusCode = statusCode
+	sr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func TestAccessTokenIsReused(t *testing.T) {
+	ctx := context.Background()
+	api := fakeapi.NewServer()
+	defer api.Close()
+	var counter tokenCounter
+	c := newTokenCountingClient(t, api, &counter)
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetNetworkById(ctx, fmt.Sprintf("missing-%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if tokens, unauthorized := counter.counts(); tokens != 1 || unauthorized != 0 {
+		t.Errorf("expected a single token request and no 401, got %d token requests and %d 401s", tokens, unauthorized)
+	}
+}
+
+// TestAccessTokenRefreshedBeforeExpiry checks that a token that expires within
+// the refresh margin is replaced before it is sent, rather than after the API
+// rejected it.
+func TestAccessTokenRefreshedBeforeExpiry(t *testing.T) {
+	ctx := context.Background()
+	api := fakeapi.NewServer()
+	defer api.Close()
+	api.TokenLifetime = 30
+	var counter tokenCounter
+	c := newTokenCountingClient(t, api, &counter)
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetNetworkById(ctx, fmt.Sprintf("missing-%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if tokens, unauthorized := counter.counts(); tokens != 4 || unauthorized != 0 {
+		t.Errorf("expected 4 token requests and no 401, got %d token requests and %d 401s", tokens, unauthorized)
+	}
+}
+
+// TestAccessTokenRevoked checks that a request rejected with a 401 gets a new
+// token and is sent again, once.
+func TestAccessTokenRevoked(t *testing.T) {
+	ctx := context.Background()
+	api := fakeapi.NewServer()
+	defer api.Close()
+	var counter tokenCounter
+	c := newTokenCountingClient(t, api, &counter)
+	api.ExpireTokens()
+	network, err := c.CreateNetwork(ctx, client.Network{Name: "tf-revoked-token", InternetAccess: client.InternetAccessLocal})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokens, unauthorized := counter.counts(); tokens != 2 || unauthorized != 1 {
+		t.Errorf("expected 2 token requests and one 401, got %d token requests and %d 401s", tokens, unauthorized)
+	}
+	if networks, _ := api.Backend.GetNetworks(ctx); len(networks) != 1 || networks[0].Id != network.Id {
+		t.Errorf("expected the network to be created once, got %+v", networks)
+	}
+}
+
+// TestAccessTokenRejectedTwice checks that a request is not sent a third time
+// when the API also rejects the new token.
+func TestAccessTokenRejectedTwice(t *testing.T) {
+	ctx := context.Background()
+	api := fakeapi.NewServer()
+	defer api.Close()
+	var counter tokenCounter
+	server := newInterceptedServer(t, api, func(w http.ResponseWriter, r *http.Request) bool {
+		if strings.HasSuffix(r.URL.Path, "/oauth/token") {
+			atomic.AddInt32(&counter.tokens, 1)
+			return false
+		}
+		atomic.AddInt32(&counter.unauthorized, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+		return true
+	})
+	c, err := client.NewClient(ctx, server.URL, api.ClientId, api.ClientSecret, client.WithRequestsPerSecond(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.GetNetworkById(ctx, "missing")
+	if !client.IsUnauthorized(err) {
+		t.Errorf("expected a 401 error, got %v", err)
+	}
+	if tokens, unauthorized := counter.counts(); tokens != 2 || unauthorized != 2 {
+		t.Errorf("expected 2 token requests and two 401s, got %d token requests and %d 401s", tokens, unauthorized)
+	}
+}
+
+// TestAccessTokenSharedRefresh checks that concurrent requests rejected with a
+// 401 share a single new token.
+func TestAccessTokenSharedRefresh(t *testing.T) {
+	ctx := context.Background()
+	api := fakeapi.NewServer()
+	defer api.Close()
+	var counter tokenCounter
+	c := newTokenCountingClient(t, api, &counter)
+	api.ExpireTokens()
+	const requests = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := c.GetNetworkById(ctx, fmt.Sprintf("missing-%d", i))
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if tokens, _ := counter.counts(); tokens != 2 {
+		t.Errorf("expected 2 token requests, got %d", tokens)
+	}
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+
+// startSpan starts the span of a single HTTP call to the API, as a child of
+// the span in the context of the request. Nothing is recorded unless the
+// program installed an OpenTelemetry tracer provider.
+func startSpan(req *http.Request, attempt int) trace.Span {
+	_, span := otel.Tracer(tracerName).Start(req.Context(), "OpenVPN Cloud API "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(req.Method),
+			semconv.HTTPURLKey.String(req.URL.Redacted()),
+			attribute.String("openvpncloud.request_id", req.Header.Get(RequestIdHeader)),
+			attribute.Int("openvpncloud.attempt", attempt),
+		),
+	)
+	return span
+}
+
+// endSpan records the outcome of the HTTP call and ends its span.
+func (c *Client) endSpan(span trace.Span, res *http.Response, err error) {
+	if res != nil {
+		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(res.StatusCode))
+		span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(res.StatusCode, trace.SpanKindClient))
+	}
+	if err != nil {
+		err = c.redactError(err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -2,9 +2,10 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
+	"net/url"
 )
 
 type User struct {
@@ -20,46 +21,95 @@ type User struct {
 	Devices   []Device `json:"devices"`
 }
 
+const (
+	UserRoleMember = "MEMBER"
+	UserRoleAdmin  = "ADMIN"
+	UserRoleOwner  = "OWNER"
+
+	UserStatusActive    = "ACTIVE"
+	UserStatusSuspended = "SUSPENDED"
+	// UserStatusPending is the status of an invited user who has not signed
+	// in yet. It cannot be set through the API.
+	UserStatusPending = "PENDING"
+)
+
+// userUpdate holds the fields of a user that can be changed after it was
+// created. The username cannot be changed, and devices have their own
+// endpoints.
+type userUpdate struct {
+	Email     string `json:"email"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	GroupId   string `json:"groupId,omitempty"`
+	Role      string `json:"role,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
 type Device struct {
-	Id          string `json:"id"`
+	Id          string `json:"id,omitempty"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	IPv4Address string `json:"ipV4Address"`
 	IPv6Address string `json:"ipV6Address"`
 }
 
-func (c *Client) CreateUser(user User) (*User, error) {
-	userJson, err := json.Marshal(user)
+func (c *Client) CreateUser(ctx context.Context, user User) (*User, error) {
+	userJson, err := c.marshalUser(user)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/beta/users", c.BaseURL), bytes.NewBuffer(userJson))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL("users"), bytes.NewBuffer(userJson))
 	if err != nil {
 		return nil, err
 	}
-	body, err := c.DoRequest(req)
+	var u *User
+	err = c.createIdempotently(ctx, func() error {
+		body, err := c.doMutation(req, "users")
+		if err != nil {
+			return err
+		}
+		u = &User{}
+		return json.Unmarshal(body, u)
+	}, func() (bool, error) {
+		users, err := c.getUsers(ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, existing := range users {
+			if existing.Username == user.Username {
+				u = &existing
+				return true, nil
+			}
+		}
+		return false, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	var u User
-	err = json.Unmarshal(body, &u)
-	if err != nil {
-		return nil, err
+	if c.APIVersion == APIVersionV1 && len(u.Devices) == 0 {
+		for _, device := range user.Devices {
+			d, err := c.CreateDevice(ctx, u.Id, device)
+			if err != nil {
+				return nil, err
+			}
+			u.Devices = append(u.Devices, *d)
+		}
 	}
-	return &u, nil
+	return u, nil
 }
 
-func (c *Client) GetUser(username string, role string) (*User, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/beta/users", c.BaseURL), nil)
-	if err != nil {
-		return nil, err
-	}
-	body, err := c.DoRequest(req)
+func (c *Client) getUsers(ctx context.Context) ([]User, error) {
+	var users []User
+	err := c.getAll(ctx, c.apiPath("users"), &users)
 	if err != nil {
 		return nil, err
 	}
+	return users, nil
+}
+
+func (c *Client) GetUser(ctx context.Context, username string, role string) (*User, error) {
 	var users []User
-	err = json.Unmarshal(body, &users)
+	err := c.getFiltered(ctx, "users", url.Values{"username": {username}}, &users)
 	if err != nil {
 		return nil, err
 	}
@@ -71,8 +121,8 @@ func (c *Client) GetUser(username string, role string) (*User, error) {
 	return nil, nil
 }
 
-func (c *Client) GetUserById(userId string) (*User, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/beta/users/%s", c.BaseURL, userId), nil)
+func (c *Client) GetUserById(ctx context.Context, userId string) (*User, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL("users/%s", userId), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -88,11 +138,31 @@ func (c *Client) GetUserById(userId string) (*User, error) {
 	return &u, nil
 }
 
-func (c *Client) DeleteUser(userId string) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/beta/users/%s", c.BaseURL, userId), nil)
+func (c *Client) UpdateUser(ctx context.Context, user User) error {
+	userJson, err := json.Marshal(userUpdate{
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		GroupId:   user.GroupId,
+		Role:      user.Role,
+		Status:    user.Status,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.apiURL("users/%s", user.Id), bytes.NewBuffer(userJson))
+	if err != nil {
+		return err
+	}
+	_, err = c.doMutation(req, "users")
+	return err
+}
+
+func (c *Client) DeleteUser(ctx context.Context, userId string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.apiURL("users/%s", userId), nil)
 	if err != nil {
 		return err
 	}
-	_, err = c.DoRequest(req)
+	_, err = c.doMutation(req, "users")
 	return err
 }
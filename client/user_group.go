@@ -1,13 +1,15 @@
 package client
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
+	"net/url"
 )
 
 type UserGroup struct {
-	Id             string   `json:"id"`
+	Id             string   `json:"id,omitempty"`
 	Name           string   `json:"name"`
 	VpnRegionIds   []string `json:"vpnRegionIds"`
 	InternetAccess string   `json:"internetAccess"`
@@ -15,17 +17,24 @@ type UserGroup struct {
 	SystemSubnets  []string `json:"systemSubnets"`
 }
 
-func (c *Client) GetUserGroup(name string) (*UserGroup, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/beta/user-groups", c.BaseURL), nil)
-	if err != nil {
-		return nil, err
-	}
-	body, err := c.DoRequest(req)
+const (
+	UserGroupInternetAccessSplitTunnelOn      = "SPLIT_TUNNEL_ON"
+	UserGroupInternetAccessSplitTunnelOff     = "SPLIT_TUNNEL_OFF"
+	UserGroupInternetAccessRestrictedInternet = "RESTRICTED_INTERNET"
+)
+
+func (c *Client) GetUserGroups(ctx context.Context) ([]UserGroup, error) {
+	var userGroups []UserGroup
+	err := c.getAll(ctx, c.apiPath("user-groups"), &userGroups)
 	if err != nil {
 		return nil, err
 	}
+	return userGroups, nil
+}
+
+func (c *Client) GetUserGroup(ctx context.Context, name string) (*UserGroup, error) {
 	var userGroups []UserGroup
-	err = json.Unmarshal(body, &userGroups)
+	err := c.getFiltered(ctx, "user-groups", url.Values{"name": {name}}, &userGroups)
 	if err != nil {
 		return nil, err
 	}
@@ -36,3 +45,76 @@ func (c *Client) GetUserGroup(name string) (*UserGroup, error) {
 	}
 	return nil, nil
 }
+
+func (c *Client) GetUserGroupById(ctx context.Context, userGroupId string) (*UserGroup, error) {
+	var userGroup UserGroup
+	found, err := c.getById(ctx, "user-groups", userGroupId, &userGroup, func() (bool, error) {
+		userGroups, err := c.GetUserGroups(ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, ug := range userGroups {
+			if ug.Id == userGroupId {
+				userGroup = ug
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil || !found {
+		return nil, err
+	}
+	return &userGroup, nil
+}
+
+func (c *Client) CreateUserGroup(ctx context.Context, userGroup UserGroup) (*UserGroup, error) {
+	userGroupJson, err := json.Marshal(userGroup)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL("user-groups"), bytes.NewBuffer(userGroupJson))
+	if err != nil {
+		return nil, err
+	}
+	var ug *UserGroup
+	err = c.createIdempotently(ctx, func() error {
+		body, err := c.doMutation(req, "user-groups")
+		if err != nil {
+			return err
+		}
+		ug = &UserGroup{}
+		return json.Unmarshal(body, ug)
+	}, func() (bool, error) {
+		existing, err := c.GetUserGroup(ctx, userGroup.Name)
+		ug = existing
+		return existing != nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ug, nil
+}
+
+func (c *Client) UpdateUserGroup(ctx context.Context, userGroup UserGroup) error {
+	userGroupJson, err := json.Marshal(userGroup)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.apiURL("user-groups/%s", userGroup.Id), bytes.NewBuffer(userGroupJson))
+	if err != nil {
+		return err
+	}
+	_, err = c.doMutation(req, "user-groups")
+	return err
+}
+
+// DeleteUserGroup deletes a user group. The group of its users changes with
+// it, so the snapshots of the users are dropped as well.
+func (c *Client) DeleteUserGroup(ctx context.Context, userGroupId string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.apiURL("user-groups/%s", userGroupId), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.doMutation(req, "user-groups", "users")
+	return err
+}
@@ -1,9 +1,7 @@
 package client
 
 import (
-	"encoding/json"
-	"fmt"
-	"net/http"
+	"context"
 )
 
 type VpnRegion struct {
@@ -14,17 +12,9 @@ type VpnRegion struct {
 	RegionName string `json:"regionName"`
 }
 
-func (c *Client) GetVpnRegion(regionId string) (*VpnRegion, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/beta/regions", c.BaseURL), nil)
-	if err != nil {
-		return nil, err
-	}
-	body, err := c.DoRequest(req)
-	if err != nil {
-		return nil, err
-	}
+func (c *Client) GetVpnRegion(ctx context.Context, regionId string) (*VpnRegion, error) {
 	var vpnRegions []VpnRegion
-	err = json.Unmarshal(body, &vpnRegions)
+	err := c.getAll(ctx, c.apiPath("regions"), &vpnRegions)
 	if err != nil {
 		return nil, err
 	}
@@ -1,16 +1,37 @@
 package main
 
 import (
+	"context"
+	"log"
+	"time"
+
 	"github.com/patoarvizu/terraform-provider-openvpn-cloud/openvpncloud"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 )
 
+// version is set by goreleaser at build time.
+var version = "dev"
+
+// tracesFlushTimeout bounds the time spent flushing spans on exit, which must
+// end before Terraform stops waiting for the provider and kills it.
+const tracesFlushTimeout = time.Second
+
 func main() {
+	shutdownTracing, err := openvpncloud.ConfigureTracing(context.Background(), version)
+	if err != nil {
+		log.Printf("[WARN] Unable to configure tracing: %v", err)
+	}
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: func() *schema.Provider {
-			return openvpncloud.Provider()
+			return openvpncloud.Provider(version)
 		},
 	})
+	ctx, cancel := context.WithTimeout(context.Background(), tracesFlushTimeout)
+	defer cancel()
+	err = shutdownTracing(ctx)
+	if err != nil {
+		log.Printf("[WARN] Unable to flush traces: %v", err)
+	}
 }
@@ -0,0 +1,100 @@
+package openvpncloud
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+)
+
+func dataSourceAccessGroup() *schema.Resource {
+	return &schema.Resource{
+		Description: "Use an `openvpncloud_access_group` data source to read an existing OpenVPN Cloud access group.",
+		ReadContext: dataSourceAccessGroupRead,
+		Schema: map[string]*schema.Schema{
+			"access_group_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The access group ID.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the access group.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The description of the access group.",
+			},
+			"source": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        dataSourceAccessItemResource(false),
+				Description: "The user groups, networks and hosts that can reach the destinations.",
+			},
+			"destination": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        dataSourceAccessItemResource(true),
+				Description: "The user groups, networks, hosts and services that the sources can reach.",
+			},
+		},
+	}
+}
+
+func dataSourceAccessItemResource(destination bool) *schema.Resource {
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the items.",
+			},
+			"all_covered": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether every item of the type is covered.",
+			},
+			"children": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The IDs of the covered items.",
+			},
+		},
+	}
+	if destination {
+		r.Schema["parent"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The ID of the network or host whose services are covered.",
+		}
+	}
+	return r
+}
+
+func dataSourceAccessGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	accessGroupName := d.Get("name").(string)
+	accessGroup, err := c.GetAccessGroupByName(ctx, accessGroupName)
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if accessGroup == nil {
+		return append(diags, diag.Errorf("Access group with name %s was not found", accessGroupName)...)
+	}
+	d.Set("access_group_id", accessGroup.Id)
+	d.Set("name", accessGroup.Name)
+	d.Set("description", accessGroup.Description)
+	d.Set("source", flattenAccessItems(accessGroup.Source, false))
+	d.Set("destination", flattenAccessItems(accessGroup.Destination, true))
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+	return diags
+}
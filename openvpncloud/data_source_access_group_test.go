@@ -0,0 +1,60 @@
+package openvpncloud
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccOpenvpncloudDataSourceAccessGroup_basic(t *testing.T) {
+	dataSourceName := "data.openvpncloud_access_group.test"
+	name := acctest.RandomWithPrefix("tf-acc")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAccessGroupConfig(name, "openvpncloud_access_group.test.name"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataSourceName, "access_group_id", "openvpncloud_access_group.test", "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "name", name),
+					resource.TestCheckResourceAttr(dataSourceName, "description", "Managed by Terraform"),
+					resource.TestCheckResourceAttr(dataSourceName, "source.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "source.0.all_covered", "true"),
+					resource.TestCheckResourceAttr(dataSourceName, "destination.#", "2"),
+					resource.TestCheckTypeSetElemAttrPair(dataSourceName, "destination.0.children.*", "openvpncloud_network.test", "id"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "destination.1.parent", "openvpncloud_network.test", "id"),
+				),
+			},
+			{
+				Config:      testAccDataSourceAccessGroupConfig(name, `"tf-acc-missing"`),
+				ExpectError: regexp.MustCompile("Access group with name tf-acc-missing was not found"),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAccessGroupConfig(name string, nameExpression string) string {
+	return testAccAccessGroupConfig(name, `
+  source {
+    type        = "USER_GROUP"
+    all_covered = true
+  }
+  destination {
+    type     = "NETWORK"
+    children = [openvpncloud_network.test.id]
+  }
+  destination {
+    type        = "NETWORK"
+    parent      = openvpncloud_network.test.id
+    all_covered = true
+  }
+`) + fmt.Sprintf(`
+data "openvpncloud_access_group" "test" {
+  name = %s
+}
+`, nameExpression)
+}
@@ -50,9 +50,9 @@ func dataSourceConnector() *schema.Resource {
 }
 
 func dataSourceConnectorRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
-	connector, err := c.GetConnectorByName(d.Get("name").(string))
+	connector, err := c.GetConnectorByName(ctx, d.Get("name").(string))
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
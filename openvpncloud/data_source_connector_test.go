@@ -0,0 +1,47 @@
+package openvpncloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+)
+
+func TestAccOpenvpncloudDataSourceConnector_basic(t *testing.T) {
+	dataSourceName := "data.openvpncloud_connector.test"
+	name := acctest.RandomWithPrefix("tf-acc")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceConnectorConfig(name, name+"-extra"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "name", name+"-extra"),
+					resource.TestCheckResourceAttr(dataSourceName, "network_item_type", client.NetworkItemTypeNetwork),
+					resource.TestCheckResourceAttrPair(dataSourceName, "network_item_id", "openvpncloud_network.test", "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "vpn_region_id", testAccVpnRegionId()),
+					resource.TestCheckResourceAttrPair(dataSourceName, "ip_v4_address", "openvpncloud_connector.test", "ip_v4_address"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "ip_v6_address", "openvpncloud_connector.test", "ip_v6_address"),
+				),
+			},
+			{
+				Config: testAccDataSourceConnectorConfig(name, name+"-renamed"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "name", name+"-renamed"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "ip_v4_address", "openvpncloud_connector.test", "ip_v4_address"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceConnectorConfig(networkName string, connectorName string) string {
+	return testAccConnectorConfig(networkName, connectorName) + `
+data "openvpncloud_connector" "test" {
+  name       = openvpncloud_connector.test.name
+  depends_on = [openvpncloud_connector.test]
+}
+`
+}
@@ -82,9 +82,9 @@ func dataSourceHost() *schema.Resource {
 }
 
 func dataSourceHostRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
-	host, err := c.GetHostByName(d.Get("name").(string))
+	host, err := c.GetHostByName(ctx, d.Get("name").(string))
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
@@ -0,0 +1,46 @@
+package openvpncloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+)
+
+func TestAccOpenvpncloudDataSourceHost_basic(t *testing.T) {
+	dataSourceName := "data.openvpncloud_host.test"
+	name := acctest.RandomWithPrefix("tf-acc")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceHostConfig(name, name+"-a"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "name", name),
+					resource.TestCheckResourceAttr(dataSourceName, "internet_access", client.InternetAccessLocal),
+					resource.TestCheckResourceAttr(dataSourceName, "connectors.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "connectors.0.name", name+"-a"),
+					resource.TestCheckResourceAttr(dataSourceName, "connectors.0.network_item_type", client.NetworkItemTypeHost),
+					resource.TestCheckResourceAttrPair(dataSourceName, "connectors.0.network_item_id", "openvpncloud_host.test", "id"),
+				),
+			},
+			{
+				Config: testAccDataSourceHostConfig(name, name+"-a", name+"-b"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "connectors.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceHostConfig(name string, connectorNames ...string) string {
+	return testAccHostConfig(name, "Managed by Terraform", connectorNames...) + `
+data "openvpncloud_host" "test" {
+  name       = openvpncloud_host.test.name
+  depends_on = [openvpncloud_host.test]
+}
+`
+}
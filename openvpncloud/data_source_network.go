@@ -25,6 +25,11 @@ func dataSourceNetwork() *schema.Resource {
 				Required:    true,
 				Description: "The network name.",
 			},
+			"description": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The description of the network.",
+			},
 			"egress": {
 				Type:        schema.TypeBool,
 				Computed:    true,
@@ -116,10 +121,10 @@ func dataSourceNetwork() *schema.Resource {
 }
 
 func dataSourceNetworkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	networkName := d.Get("name").(string)
-	network, err := c.GetNetworkByName(networkName)
+	network, err := c.GetNetworkByName(ctx, networkName)
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
@@ -44,9 +44,9 @@ func dataSourceNetworkRoutes() *schema.Resource {
 }
 
 func dataSourceNetworkRoutesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
-	routes, err := c.GetRoutes(d.Get("network_item_id").(string))
+	routes, err := c.GetRoutes(ctx, d.Get("network_item_id").(string))
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
@@ -0,0 +1,49 @@
+package openvpncloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+)
+
+func TestAccOpenvpncloudDataSourceNetworkRoutes_basic(t *testing.T) {
+	dataSourceName := "data.openvpncloud_network_routes.test"
+	name := acctest.RandomWithPrefix("tf-acc")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceNetworkRoutesConfig(name, client.RouteTypeIPV4, "10.1.0.0/24"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "routes.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(dataSourceName, "routes.*", map[string]string{
+						"type":  client.RouteTypeIPV4,
+						"value": "10.1.0.0/24",
+					}),
+				),
+			},
+			{
+				Config: testAccDataSourceNetworkRoutesConfig(name, client.RouteTypeDomain, "example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "routes.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(dataSourceName, "routes.*", map[string]string{
+						"type":  client.RouteTypeDomain,
+						"value": "example.com",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceNetworkRoutesConfig(name string, routeType string, value string) string {
+	return testAccRouteConfig(name, routeType, value) + `
+data "openvpncloud_network_routes" "test" {
+  network_item_id = openvpncloud_route.test.network_item_id
+  depends_on      = [openvpncloud_route.test]
+}
+`
+}
@@ -0,0 +1,50 @@
+package openvpncloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+)
+
+func TestAccOpenvpncloudDataSourceNetwork_basic(t *testing.T) {
+	dataSourceName := "data.openvpncloud_network.test"
+	name := acctest.RandomWithPrefix("tf-acc")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceNetworkConfig(name, "Created by Terraform"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataSourceName, "network_id", "openvpncloud_network.test", "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "name", name),
+					resource.TestCheckResourceAttr(dataSourceName, "egress", "true"),
+					resource.TestCheckResourceAttr(dataSourceName, "internet_access", client.InternetAccessLocal),
+					resource.TestCheckResourceAttr(dataSourceName, "routes.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "routes.0.type", client.RouteTypeIPV4),
+					resource.TestCheckResourceAttr(dataSourceName, "routes.0.subnet", "10.0.0.0/18"),
+					resource.TestCheckResourceAttr(dataSourceName, "connectors.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "connectors.0.name", name+"-connector"),
+				),
+			},
+			{
+				Config: testAccDataSourceNetworkConfig(name+"-updated", "Updated by Terraform"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataSourceName, "network_id", "openvpncloud_network.test", "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "name", name+"-updated"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceNetworkConfig(name string, description string) string {
+	return testAccNetworkConfig(name, description, "10.0.0.0/18") + `
+data "openvpncloud_network" "test" {
+  name       = openvpncloud_network.test.name
+  depends_on = [openvpncloud_network.test]
+}
+`
+}
@@ -99,10 +99,10 @@ func dataSourceUser() *schema.Resource {
 }
 
 func dataSourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	userName := d.Get("username").(string)
-	user, err := c.GetUser(userName, d.Get("role").(string))
+	user, err := c.GetUser(ctx, userName, d.Get("role").(string))
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
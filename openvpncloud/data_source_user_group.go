@@ -36,7 +36,7 @@ func dataSourceUserGroup() *schema.Resource {
 			"internet_access": {
 				Type:        schema.TypeString,
 				Computed:    true,
-				Description: "The type of internet access provided. Valid values are `BLOCKED`, `GLOBAL_INTERNET`, or `LOCAL`. Defaults to `LOCAL`.",
+				Description: "The type of internet access provided. Valid values are `SPLIT_TUNNEL_ON`, `SPLIT_TUNNEL_OFF`, or `RESTRICTED_INTERNET`.",
 			},
 			"max_device": {
 				Type:        schema.TypeInt,
@@ -56,10 +56,10 @@ func dataSourceUserGroup() *schema.Resource {
 }
 
 func dataSourceUserGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	userGroupName := d.Get("name").(string)
-	userGroup, err := c.GetUserGroup(userGroupName)
+	userGroup, err := c.GetUserGroup(ctx, userGroupName)
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
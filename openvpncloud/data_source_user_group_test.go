@@ -0,0 +1,41 @@
+package openvpncloud
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client/fake"
+)
+
+func TestAccOpenvpncloudDataSourceUserGroup_basic(t *testing.T) {
+	dataSourceName := "data.openvpncloud_user_group.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceUserGroupConfig(fake.DefaultUserGroupName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "name", fake.DefaultUserGroupName),
+					resource.TestCheckResourceAttrSet(dataSourceName, "user_group_id"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "internet_access"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "max_device"),
+				),
+			},
+			{
+				Config:      testAccDataSourceUserGroupConfig("tf-acc-missing"),
+				ExpectError: regexp.MustCompile("User group with name tf-acc-missing was not found"),
+			},
+		},
+	})
+}
+
+func testAccDataSourceUserGroupConfig(name string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+data "openvpncloud_user_group" "test" {
+  name = %q
+}
+`, name)
+}
@@ -0,0 +1,48 @@
+package openvpncloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccOpenvpncloudDataSourceUser_basic(t *testing.T) {
+	dataSourceName := "data.openvpncloud_user.test"
+	username := acctest.RandomWithPrefix("tf-acc")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceUserConfig(username, "Jane"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataSourceName, "user_id", "openvpncloud_user.test", "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "username", username),
+					resource.TestCheckResourceAttr(dataSourceName, "role", "MEMBER"),
+					resource.TestCheckResourceAttr(dataSourceName, "email", username+"@example.com"),
+					resource.TestCheckResourceAttr(dataSourceName, "first_name", "Jane"),
+					resource.TestCheckResourceAttr(dataSourceName, "last_name", "Doe"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "status"),
+				),
+			},
+			{
+				Config: testAccDataSourceUserConfig(username, "Janet"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataSourceName, "user_id", "openvpncloud_user.test", "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "first_name", "Janet"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceUserConfig(username string, firstName string) string {
+	return testAccUserConfig(username, firstName, "", "MEMBER", "") + `
+data "openvpncloud_user" "test" {
+  username   = openvpncloud_user.test.username
+  role       = "MEMBER"
+  depends_on = [openvpncloud_user.test]
+}
+`
+}
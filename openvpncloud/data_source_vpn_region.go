@@ -45,10 +45,10 @@ func dataSourceVpnRegion() *schema.Resource {
 }
 
 func dataSourceVpnRegionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	vpnRegionId := d.Get("region_id").(string)
-	vpnRegion, err := c.GetVpnRegion(vpnRegionId)
+	vpnRegion, err := c.GetVpnRegion(ctx, vpnRegionId)
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
@@ -0,0 +1,41 @@
+package openvpncloud
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccOpenvpncloudDataSourceVpnRegion_basic(t *testing.T) {
+	dataSourceName := "data.openvpncloud_vpn_region.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceVpnRegionConfig(testAccVpnRegionId()),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "region_id", testAccVpnRegionId()),
+					resource.TestCheckResourceAttrSet(dataSourceName, "continent"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "country"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "country_iso"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "region_name"),
+				),
+			},
+			{
+				Config:      testAccDataSourceVpnRegionConfig("tf-acc-missing"),
+				ExpectError: regexp.MustCompile("VPN region with id tf-acc-missing was not found"),
+			},
+		},
+	})
+}
+
+func testAccDataSourceVpnRegionConfig(regionId string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+data "openvpncloud_vpn_region" "test" {
+  region_id = %q
+}
+`, regionId)
+}
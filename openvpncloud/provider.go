@@ -2,10 +2,16 @@ package openvpncloud
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"net"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
 )
 
@@ -13,8 +19,8 @@ type Token struct {
 	AccessToken string `json:"access_token"`
 }
 
-func Provider() *schema.Provider {
-	return &schema.Provider{
+func Provider(version string) *schema.Provider {
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"client_id": {
 				Type:        schema.TypeString,
@@ -32,41 +38,223 @@ func Provider() *schema.Provider {
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			"api_version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      client.DefaultAPIVersion,
+				ValidateFunc: validation.StringInSlice([]string{client.APIVersionBeta, client.APIVersionV1}, false),
+				Description:  "The version of the OpenVPN Cloud API to use. Valid values are `beta` or `v1`. Defaults to `beta`.",
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      client.DefaultMaxRetries,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The maximum number of times a request is retried after a rate limit, a server error or a transient network error. Defaults to `3`.",
+			},
+			"retry_max_wait": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(client.DefaultRetryMaxWait / time.Second),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The maximum number of seconds to wait between two retries. Defaults to `30`.",
+			},
+			"page_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      client.DefaultPageSize,
+				ValidateFunc: validation.IntBetween(1, 1000),
+				Description:  "The number of items requested per page when listing objects from the API. Defaults to `100`.",
+			},
+			"cache_ttl": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(client.DefaultCacheTTL / time.Second),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The number of seconds for which a list of objects read from the API is reused before it is read again. Set to `0` to always read the latest list. Defaults to `30`.",
+			},
+			"requests_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      client.DefaultRequestsPerSecond,
+				ValidateFunc: validation.FloatAtLeast(0),
+				Description:  "The maximum number of requests per second sent to the API, shared by all the operations Terraform runs in parallel. Set to `0` to disable the limit. Defaults to `10`.",
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      client.DefaultMaxConcurrentRequests,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The maximum number of requests to the API that can be in flight at the same time. Set to `0` to disable the limit. Defaults to `5`.",
+			},
+			"request_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(client.DefaultRequestTimeout / time.Second),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The number of seconds after which a single request to the API is aborted. Defaults to `10`.",
+			},
+			"proxy_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsURLWithScheme([]string{"http", "https", "socks5"}),
+				Description:  "The URL of the proxy used to reach the API. If not provided, the proxy is read from the `HTTPS_PROXY` and `NO_PROXY` environment variables.",
+			},
+			"ca_bundle_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The path to a PEM encoded bundle of additional certificate authorities to trust when connecting to the API.",
+			},
+			"client_cert_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"client_key_file"},
+				Description:  "The path to a PEM encoded client certificate used for mutual TLS authentication.",
+			},
+			"client_key_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"client_cert_file"},
+				Description:  "The path to the PEM encoded private key of `client_cert_file`.",
+			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Refuses every request that would create, update or delete an object, while reads keep working. Use it to run plans with credentials that must not make changes. Defaults to `false`.",
+			},
+			"audit_log_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The path of a file to which an entry is appended, as a line of JSON, for every create, update and delete request sent to the API, including the failed ones. Updates and deletes also record the object as it was before the request.",
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Disables the verification of the API server certificate. This should never be used outside of testing.",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"openvpncloud_network":    resourceNetwork(),
-			"openvpncloud_connector":  resourceConnector(),
-			"openvpncloud_route":      resourceRoute(),
-			"openvpncloud_dns_record": resourceDnsRecord(),
-			"openvpncloud_user":       resourceUser(),
-			"openvpncloud_host":       resourceHost(),
+			"openvpncloud_network":          resourceNetwork(),
+			"openvpncloud_connector":        resourceConnector(),
+			"openvpncloud_route":            resourceRoute(),
+			"openvpncloud_dns_record":       resourceDnsRecord(),
+			"openvpncloud_device":           resourceDevice(),
+			"openvpncloud_user":             resourceUser(),
+			"openvpncloud_user_group":       resourceUserGroup(),
+			"openvpncloud_host":             resourceHost(),
+			"openvpncloud_access_group":     resourceAccessGroup(),
+			"openvpncloud_location_context": resourceLocationContext(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"openvpncloud_network":        dataSourceNetwork(),
 			"openvpncloud_connector":      dataSourceConnector(),
 			"openvpncloud_user":           dataSourceUser(),
 			"openvpncloud_user_group":     dataSourceUserGroup(),
+			"openvpncloud_access_group":   dataSourceAccessGroup(),
 			"openvpncloud_vpn_region":     dataSourceVpnRegion(),
 			"openvpncloud_network_routes": dataSourceNetworkRoutes(),
 			"openvpncloud_host":           dataSourceHost(),
 		},
-		ConfigureContextFunc: providerConfigure,
+	}
+	for name, r := range p.ResourcesMap {
+		traceResource(name, "managed", r)
+	}
+	for name, r := range p.DataSourcesMap {
+		traceResource(name, "data", r)
+	}
+	p.ConfigureContextFunc = traceConfigure(providerConfigure(version, p))
+	return p
+}
+
+func providerConfigure(version string, p *schema.Provider) schema.ConfigureContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		clientId := d.Get("client_id").(string)
+		clientSecret := d.Get("client_secret").(string)
+		baseUrl := d.Get("base_url").(string)
+		var diags diag.Diagnostics
+		opts := []client.Option{
+			client.WithAPIVersion(d.Get("api_version").(string)),
+			client.WithMaxRetries(d.Get("max_retries").(int)),
+			client.WithRetryMaxWait(time.Duration(d.Get("retry_max_wait").(int)) * time.Second),
+			client.WithPageSize(d.Get("page_size").(int)),
+			client.WithCacheTTL(time.Duration(d.Get("cache_ttl").(int)) * time.Second),
+			client.WithRequestsPerSecond(d.Get("requests_per_second").(float64)),
+			client.WithMaxConcurrentRequests(d.Get("max_concurrent_requests").(int)),
+			client.WithRequestTimeout(time.Duration(d.Get("request_timeout").(int)) * time.Second),
+			client.WithUserAgent(p.UserAgent("terraform-provider-openvpncloud", version)),
+			client.WithReadOnly(d.Get("read_only").(bool)),
+		}
+		if proxyURL, ok := d.GetOk("proxy_url"); ok {
+			opts = append(opts, client.WithProxyURL(proxyURL.(string)))
+		}
+		if caBundleFile, ok := d.GetOk("ca_bundle_file"); ok {
+			opts = append(opts, client.WithCABundleFile(caBundleFile.(string)))
+		}
+		if certFile, ok := d.GetOk("client_cert_file"); ok {
+			opts = append(opts, client.WithClientCertificate(certFile.(string), d.Get("client_key_file").(string)))
+		}
+		if auditLogPath, ok := d.GetOk("audit_log_path"); ok {
+			opts = append(opts, client.WithAuditLog(auditLogPath.(string)))
+		}
+		if d.Get("insecure_skip_verify").(bool) {
+			opts = append(opts, client.WithInsecureSkipVerify(true))
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "TLS certificate verification is disabled",
+				Detail:   "`insecure_skip_verify` is set, so the identity of the OpenVPN Cloud API server is not verified and its traffic, including your API credentials, can be intercepted. Never use this setting outside of testing.",
+			})
+		}
+		normalizedUrl, err := client.NormalizeBaseURL(baseUrl)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Invalid base_url",
+				Detail:   fmt.Sprintf("Error: %v", err),
+			})
+			return nil, diags
+		}
+		openVPNClient, err := client.NewClient(ctx, normalizedUrl, clientId, clientSecret, opts...)
+		if err == nil {
+			err = openVPNClient.Ping(ctx)
+		}
+		if err != nil {
+			diags = append(diags, clientDiagnostic(normalizedUrl, err))
+			return nil, diags
+		}
+		return openVPNClient, diags
 	}
 }
 
-func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-	clientId := d.Get("client_id").(string)
-	clientSecret := d.Get("client_secret").(string)
-	baseUrl := d.Get("base_url").(string)
-	openVPNClient, err := client.NewClient(baseUrl, clientId, clientSecret)
-	var diags diag.Diagnostics
-	if err != nil {
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  "Unable to create client",
-			Detail:   fmt.Sprintf("Error: %v", err),
-		})
-		return nil, diags
+// clientDiagnostic explains why the client could not get an access token
+// from, or make a first call to, the API at baseUrl.
+func clientDiagnostic(baseUrl string, err error) diag.Diagnostic {
+	d := diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  "Unable to create client",
+		Detail:   fmt.Sprintf("Error while connecting to %s: %v", baseUrl, err),
+	}
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	var netErr net.Error
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certificateErr x509.CertificateInvalidError
+	var recordHeaderErr tls.RecordHeaderError
+	switch {
+	case client.IsUnauthorized(err) || client.IsForbidden(err):
+		d.Summary = "Invalid OpenVPN Cloud credentials"
+		d.Detail = fmt.Sprintf("The OpenVPN Cloud API at %s did not accept client_id and client_secret: %v. Check that they belong to an API client of this tenant and have not been revoked.", baseUrl, err)
+	case errors.Is(err, client.ErrNotOpenVPNCloudAPI) || client.IsNotFound(err):
+		d.Summary = "Not an OpenVPN Cloud API endpoint"
+		d.Detail = fmt.Sprintf("The server at %s does not answer like the OpenVPN Cloud API: %v. Check that base_url is the API URL of your tenant, such as https://<tenant>.api.openvpn.com.", baseUrl, err)
+	case errors.As(err, &unknownAuthorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &certificateErr) || errors.As(err, &recordHeaderErr):
+		d.Summary = "TLS connection to the OpenVPN Cloud API failed"
+		d.Detail = fmt.Sprintf("Unable to establish a trusted TLS connection to %s: %v. If a proxy intercepts the traffic, set ca_bundle_file to the certificate of its authority.", baseUrl, err)
+	case errors.As(err, &dnsErr) || errors.As(err, &opErr) || (errors.As(err, &netErr) && netErr.Timeout()):
+		d.Summary = "Unable to reach the OpenVPN Cloud API"
+		d.Detail = fmt.Sprintf("Unable to connect to %s: %v. Check base_url, and proxy_url if the network requires a proxy.", baseUrl, err)
 	}
-	return openVPNClient, nil
+	return d
 }
@@ -0,0 +1,438 @@
+package openvpncloud
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client/fake"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client/fakeapi"
+)
+
+// When OPENVPN_CLOUD_FAKE_API is set, the acceptance tests run against an
+// in-process fake of the OpenVPN Cloud API instead of a real tenant, so they
+// need neither network access nor credentials.
+const testAccFakeAPIEnvVar = "OPENVPN_CLOUD_FAKE_API"
+
+var testAccFakeServer *fakeapi.Server
+
+var testAccProviderFactories = map[string]func() (*schema.Provider, error){
+	"openvpncloud": func() (*schema.Provider, error) {
+		return Provider("test"), nil
+	},
+}
+
+func TestMain(m *testing.M) {
+	if os.Getenv(testAccFakeAPIEnvVar) != "" {
+		testAccFakeServer = fakeapi.NewServer()
+		os.Setenv("OPENVPN_CLOUD_BASE_URL", testAccFakeServer.URL)
+		os.Setenv("OPENVPN_CLOUD_CLIENT_ID", testAccFakeServer.ClientId)
+		os.Setenv("OPENVPN_CLOUD_CLIENT_SECRET", testAccFakeServer.ClientSecret)
+	}
+	code := m.Run()
+	if testAccFakeServer != nil {
+		testAccFakeServer.Close()
+	}
+	os.Exit(code)
+}
+
+func TestProvider(t *testing.T) {
+	if err := Provider("test").InternalValidate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestProviderConfigureDiagnostics(t *testing.T) {
+	api := fakeapi.NewServer()
+	defer api.Close()
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	website := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html><body>Welcome</body></html>")
+	}))
+	defer website.Close()
+	untrusted := httptest.NewTLSServer(api)
+	defer untrusted.Close()
+	closed := httptest.NewServer(api)
+	closed.Close()
+
+	for name, tc := range map[string]struct {
+		baseUrl      string
+		clientSecret string
+		summary      string
+	}{
+		"valid":            {baseUrl: api.URL + "/", clientSecret: api.ClientSecret},
+		"bad credentials":  {baseUrl: api.URL + "/", clientSecret: "wrong", summary: "Invalid OpenVPN Cloud credentials"},
+		"not found":        {baseUrl: notFound.URL + "/", clientSecret: api.ClientSecret, summary: "Not an OpenVPN Cloud API endpoint"},
+		"not the API":      {baseUrl: website.URL + "/", clientSecret: api.ClientSecret, summary: "Not an OpenVPN Cloud API endpoint"},
+		"untrusted TLS":    {baseUrl: untrusted.URL + "/", clientSecret: api.ClientSecret, summary: "TLS connection to the OpenVPN Cloud API failed"},
+		"unreachable":      {baseUrl: closed.URL + "/", clientSecret: api.ClientSecret, summary: "Unable to reach the OpenVPN Cloud API"},
+		"invalid base_url": {baseUrl: "ftp://example.com", clientSecret: api.ClientSecret, summary: "Invalid base_url"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			diags := Provider("test").Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+				"base_url":      tc.baseUrl,
+				"client_id":     api.ClientId,
+				"client_secret": tc.clientSecret,
+				"max_retries":   0,
+			}))
+			if tc.summary == "" {
+				if diags.HasError() {
+					t.Fatalf("unexpected diagnostics: %+v", diags)
+				}
+				return
+			}
+			if len(diags) != 1 || diags[0].Summary != tc.summary {
+				t.Fatalf("expected the diagnostic %q, got %+v", tc.summary, diags)
+			}
+			if normalized := strings.TrimSuffix(tc.baseUrl, "/"); tc.summary != "Invalid base_url" && !strings.Contains(diags[0].Detail, normalized) {
+				t.Errorf("expected the detail to include %s, got %q", normalized, diags[0].Detail)
+			}
+			if strings.Contains(diags[0].Detail, api.ClientSecret) {
+				t.Errorf("the detail includes the client secret: %q", diags[0].Detail)
+			}
+		})
+	}
+}
+
+// TestProviderConfigureRedactsCredentials checks that the diagnostics of a
+// failed configuration include neither the client secret nor the access
+// token, even when the API repeats them in its error.
+func TestProviderConfigureRedactsCredentials(t *testing.T) {
+	const clientSecret = "s3cr3t-d0-n0t-sh0w"
+	for name, tc := range map[string]struct {
+		path       string
+		statusCode int
+	}{
+		"server error":   {path: "/api/beta/regions", statusCode: http.StatusInternalServerError},
+		"unauthorized":   {path: "/api/beta/regions", statusCode: http.StatusUnauthorized},
+		"token rejected": {path: "/api/beta/oauth/token", statusCode: http.StatusUnauthorized},
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := fakeapi.NewServer()
+			defer api.Close()
+			api.ClientSecret = clientSecret
+			var mutex sync.Mutex
+			var credentials []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authorization := r.Header.Get("Authorization")
+				mutex.Lock()
+				credentials = append(credentials, authorization, strings.TrimPrefix(authorization, "Bearer "))
+				mutex.Unlock()
+				if r.URL.Path != tc.path {
+					api.ServeHTTP(w, r)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.statusCode)
+				json.NewEncoder(w).Encode(map[string]string{
+					"errorCode":    "ECHO",
+					"errorMessage": "rejected the credentials " + authorization + " and " + clientSecret,
+				})
+			}))
+			defer server.Close()
+			diags := Provider("test").Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+				"base_url":      server.URL,
+				"client_id":     api.ClientId,
+				"client_secret": clientSecret,
+				"max_retries":   0,
+			}))
+			if !diags.HasError() {
+				t.Fatal("expected the configuration to fail")
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			for _, d := range diags {
+				for _, secret := range append(credentials, clientSecret) {
+					if secret != "" && (strings.Contains(d.Summary, secret) || strings.Contains(d.Detail, secret)) {
+						t.Errorf("the diagnostic includes the credential %q: %+v", secret, d)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestResourceDeleteNotFound checks that deleting an object that is already
+// gone succeeds, as it does when a retried DELETE finds that the first one was
+// applied.
+func TestResourceDeleteNotFound(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClient()
+	for name, raw := range map[string]map[string]interface{}{
+		"openvpncloud_access_group":     {},
+		"openvpncloud_connector":        {"network_item_id": "missing", "network_item_type": client.NetworkItemTypeNetwork},
+		"openvpncloud_device":           {"user_id": "missing"},
+		"openvpncloud_dns_record":       {},
+		"openvpncloud_host":             {},
+		"openvpncloud_location_context": {},
+		"openvpncloud_network":          {},
+		"openvpncloud_route":            {"network_item_id": "missing"},
+		"openvpncloud_user":             {},
+		"openvpncloud_user_group":       {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := Provider("test").ResourcesMap[name]
+			d := schema.TestResourceDataRaw(t, r.Schema, raw)
+			d.SetId("missing")
+			if diags := r.DeleteContext(ctx, d, c); diags.HasError() {
+				t.Errorf("unexpected diagnostics: %+v", diags)
+			}
+		})
+	}
+}
+
+// testResourceApply plans raw as the configuration of r against state, and
+// applies the plan, like terraform apply does, with meta as the provider meta.
+// It returns the new state, which is nil once the resource is destroyed.
+func testResourceApply(t *testing.T, r *schema.Resource, state *terraform.InstanceState, raw map[string]interface{}, meta interface{}) *terraform.InstanceState {
+	t.Helper()
+	ctx := context.Background()
+	diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), meta)
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	if diff == nil {
+		return state
+	}
+	state, diags := r.Apply(ctx, state, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("apply failed: %+v", diags)
+	}
+	return state
+}
+
+func TestAccOpenvpncloudProvider_auditLog(t *testing.T) {
+	resourceName := "openvpncloud_dns_record.test"
+	auditLogPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	domain := acctest.RandomWithPrefix("tf-acc") + ".example.com"
+	config := func(ipV4Address string) string {
+		return fmt.Sprintf(`
+provider "openvpncloud" {
+  base_url       = %q
+  audit_log_path = %q
+}
+
+resource "openvpncloud_dns_record" "test" {
+  domain          = %q
+  ip_v4_addresses = [%q]
+}
+`, os.Getenv("OPENVPN_CLOUD_BASE_URL"), auditLogPath, domain, ipV4Address)
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckAuditEntry(auditLogPath, resourceName, "delete"),
+		Steps: []resource.TestStep{
+			{
+				Config: config("10.0.0.1"),
+				Check:  testAccCheckAuditEntry(auditLogPath, resourceName, "create"),
+			},
+			{
+				Config: config("10.0.0.2"),
+				Check:  testAccCheckAuditEntry(auditLogPath, resourceName, "update"),
+			},
+		},
+	})
+}
+
+func TestAccOpenvpncloudProvider_readOnly(t *testing.T) {
+	providerConfig := fmt.Sprintf(`
+provider "openvpncloud" {
+  base_url  = %q
+  read_only = true
+}
+`, os.Getenv("OPENVPN_CLOUD_BASE_URL"))
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+data "openvpncloud_vpn_region" "test" {
+  region_id = %q
+}
+`, testAccVpnRegionId()),
+				Check: resource.TestCheckResourceAttr("data.openvpncloud_vpn_region.test", "region_id", testAccVpnRegionId()),
+			},
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "openvpncloud_dns_record" "test" {
+  domain          = %q
+  ip_v4_addresses = ["10.0.0.1"]
+}
+`, acctest.RandomWithPrefix("tf-acc")+".example.com"),
+				ExpectError: regexp.MustCompile("refusing to send POST .*read-only"),
+			},
+		},
+	})
+}
+
+// testAccCheckAuditEntry checks that the last entry of the audit log records a
+// successful operation on the object behind the resource. The resource is
+// looked up by type in the state, so that the check also works after it has
+// been destroyed.
+func testAccCheckAuditEntry(auditLogPath string, name string, operation string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		f, err := os.Open(auditLogPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		var last client.AuditEntry
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			err = json.Unmarshal(scanner.Bytes(), &last)
+			if err != nil {
+				return fmt.Errorf("invalid audit log entry %q: %w", scanner.Text(), err)
+			}
+		}
+		if err = scanner.Err(); err != nil {
+			return err
+		}
+		if last.Operation != operation || last.Outcome != client.AuditOutcomeSucceeded || last.ObjectType != "dns-records" {
+			return fmt.Errorf("unexpected last audit log entry: %+v", last)
+		}
+		if rs, ok := s.RootModule().Resources[name]; ok && rs.Primary.ID != last.ObjectId {
+			return fmt.Errorf("audit log entry is for object %q, expected %q", last.ObjectId, rs.Primary.ID)
+		}
+		return nil
+	}
+}
+
+func testAccPreCheck(t *testing.T) {
+	for _, v := range []string{"OPENVPN_CLOUD_BASE_URL", "OPENVPN_CLOUD_CLIENT_ID", "OPENVPN_CLOUD_CLIENT_SECRET"} {
+		if os.Getenv(v) == "" {
+			t.Fatalf("%s must be set for acceptance tests, or set %s to run them against a fake API", v, testAccFakeAPIEnvVar)
+		}
+	}
+}
+
+// testAccProviderConfig returns the provider block shared by every test
+// configuration.
+func testAccProviderConfig() string {
+	return fmt.Sprintf(`
+provider "openvpncloud" {
+  base_url = %q
+}
+`, os.Getenv("OPENVPN_CLOUD_BASE_URL"))
+}
+
+// testAccVpnRegionId returns the VPN region in which test connectors are
+// deployed. It can be overridden with OPENVPN_CLOUD_TEST_VPN_REGION_ID when
+// running against a real tenant.
+func testAccVpnRegionId() string {
+	if region := os.Getenv("OPENVPN_CLOUD_TEST_VPN_REGION_ID"); region != "" {
+		return region
+	}
+	return fake.DefaultVpnRegions[0].Id
+}
+
+// testAccAPI returns a client for the tenant the tests run against, used to
+// check and alter objects behind Terraform's back.
+func testAccAPI() (client.OpenVPNCloudAPI, error) {
+	if testAccFakeServer != nil {
+		return testAccFakeServer.Backend, nil
+	}
+	return client.NewClient(context.Background(), os.Getenv("OPENVPN_CLOUD_BASE_URL"), os.Getenv("OPENVPN_CLOUD_CLIENT_ID"), os.Getenv("OPENVPN_CLOUD_CLIENT_SECRET"))
+}
+
+// testAccExists is a generic lookup of the object behind a resource. It
+// returns false when the object does not exist, whether the API reports it as
+// a 404 or as an empty result.
+type testAccExists func(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) (bool, error)
+
+func testAccCheckExists(name string, exists testAccExists) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("resource %s has no ID set", name)
+		}
+		c, err := testAccAPI()
+		if err != nil {
+			return err
+		}
+		found, err := exists(c, rs)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("%s %s does not exist", rs.Type, rs.Primary.ID)
+		}
+		return nil
+	}
+}
+
+func testAccCheckDestroy(resourceType string, exists testAccExists) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		c, err := testAccAPI()
+		if err != nil {
+			return err
+		}
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+			found, err := exists(c, rs)
+			if err != nil {
+				return err
+			}
+			if found {
+				return fmt.Errorf("%s %s still exists", rs.Type, rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+// testAccDeleteOutOfBand deletes the object behind a resource directly through
+// the API, so that the next refresh finds it gone.
+func testAccDeleteOutOfBand(name string, destroy func(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) error) resource.TestCheckFunc {
+	return testAccAlterOutOfBand(name, destroy)
+}
+
+// testAccAlterOutOfBand changes the object behind a resource directly through
+// the API, so that the next refresh finds it drifted.
+func testAccAlterOutOfBand(name string, alter func(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) error) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", name)
+		}
+		c, err := testAccAPI()
+		if err != nil {
+			return err
+		}
+		return alter(c, rs)
+	}
+}
+
+// testAccFound interprets the result of a lookup by id, where ok reports
+// whether the lookup returned an object.
+func testAccFound(ok bool, err error) (bool, error) {
+	if client.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return ok, nil
+}
@@ -0,0 +1,257 @@
+package openvpncloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+)
+
+var accessItemTypes = []string{client.AccessItemTypeUserGroup, client.AccessItemTypeNetwork, client.AccessItemTypeHost}
+
+func resourceAccessGroup() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Use `openvpncloud_access_group` to control which user groups, networks and hosts can reach each other.",
+		CreateContext: resourceAccessGroupCreate,
+		ReadContext:   resourceAccessGroupRead,
+		UpdateContext: resourceAccessGroupUpdate,
+		DeleteContext: resourceAccessGroupDelete,
+		CustomizeDiff: resourceAccessGroupCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringLenBetween(1, 40),
+				Description:  "The name of the access group.",
+			},
+			"description": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "Managed by Terraform",
+				ValidateFunc: validation.StringLenBetween(1, 120),
+				Description:  "The description for the UI. Defaults to `Managed by Terraform`.",
+			},
+			"source": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem:        accessItemResource(false),
+				Description: "The user groups, networks and hosts that can reach the destinations. Can be defined more than once.",
+			},
+			"destination": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem:        accessItemResource(true),
+				Description: "The user groups, networks, hosts and services that the sources can reach. Can be defined more than once.",
+			},
+		},
+	}
+}
+
+// accessItemResource returns the schema of a source or destination block.
+// Only destinations can narrow a network or host down to its services.
+func accessItemResource(destination bool) *schema.Resource {
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(accessItemTypes, false),
+				Description:  "The type of the items. Valid values are `USER_GROUP`, `NETWORK`, or `HOST`.",
+			},
+			"all_covered": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Covers every item of the type, including the ones created later. `children` must be empty when it is set.",
+			},
+			"children": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The IDs of the items of the type. Required unless `all_covered` is set.",
+			},
+		},
+	}
+	if destination {
+		r.Schema["parent"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The ID of a network or host. When it is set, `children` are the IDs of services of that network or host, and `all_covered` covers all of its services.",
+		}
+	}
+	return r
+}
+
+func resourceAccessGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	ag := resourceDataToAccessGroup(d)
+	accessGroup, err := c.CreateAccessGroup(ctx, ag)
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	d.SetId(accessGroup.Id)
+	return append(diags, resourceAccessGroupRead(ctx, d, m)...)
+}
+
+func resourceAccessGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	accessGroup, err := c.GetAccessGroupById(ctx, d.Id())
+	if client.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if accessGroup == nil {
+		d.SetId("")
+		return diags
+	}
+	d.Set("name", accessGroup.Name)
+	d.Set("description", accessGroup.Description)
+	d.Set("source", flattenAccessItems(accessGroup.Source, false))
+	d.Set("destination", flattenAccessItems(accessGroup.Destination, true))
+	return diags
+}
+
+func resourceAccessGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	ag := resourceDataToAccessGroup(d)
+	ag.Id = d.Id()
+	err := c.UpdateAccessGroup(ctx, ag)
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	return append(diags, resourceAccessGroupRead(ctx, d, m)...)
+}
+
+func resourceAccessGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	err := c.DeleteAccessGroup(ctx, d.Id())
+	if err != nil && !client.IsNotFound(err) {
+		return append(diags, diag.FromErr(err)...)
+	}
+	return diags
+}
+
+func resourceDataToAccessGroup(d *schema.ResourceData) client.AccessGroup {
+	return client.AccessGroup{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Source:      expandAccessItems(d.Get("source").([]interface{})),
+		Destination: expandAccessItems(d.Get("destination").([]interface{})),
+	}
+}
+
+func expandAccessItems(blocks []interface{}) []client.AccessItem {
+	items := make([]client.AccessItem, 0, len(blocks))
+	for _, b := range blocks {
+		block := b.(map[string]interface{})
+		item := client.AccessItem{
+			Type:       block["type"].(string),
+			AllCovered: block["all_covered"].(bool),
+			Children:   getAddressesSlice(block["children"].(*schema.Set).List()),
+		}
+		if parent, ok := block["parent"]; ok {
+			item.Parent = parent.(string)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func flattenAccessItems(items []client.AccessItem, destination bool) []interface{} {
+	blocks := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		block := map[string]interface{}{
+			"type":        item.Type,
+			"all_covered": item.AllCovered,
+			"children":    item.Children,
+		}
+		if destination {
+			block["parent"] = item.Parent
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// resourceAccessGroupCustomizeDiff checks the rules between the fields of a
+// block, which the schema cannot express, so that they are reported when
+// planning. Fields that are not known yet are not checked.
+func resourceAccessGroupCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	var problems []string
+	for _, block := range []string{"source", "destination"} {
+		if !d.NewValueKnown(block) {
+			continue
+		}
+		for i := range d.Get(block).([]interface{}) {
+			problems = append(problems, validateAccessItem(d, block, i)...)
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validateAccessItem checks the i-th block of the source or destination.
+func validateAccessItem(d *schema.ResourceDiff, block string, i int) []string {
+	var problems []string
+	key := fmt.Sprintf("%s.%d", block, i)
+	if children, ok := configuredChildren(d, block, i); ok && d.NewValueKnown(key+".all_covered") {
+		allCovered := d.Get(key + ".all_covered").(bool)
+		if allCovered && children > 0 {
+			problems = append(problems, fmt.Sprintf("%s %d: children must be empty when all_covered is set", block, i))
+		}
+		if !allCovered && children == 0 {
+			problems = append(problems, fmt.Sprintf("%s %d: children must be set unless all_covered is set", block, i))
+		}
+	}
+	if block == "destination" && d.NewValueKnown(key+".type") && d.Get(key+".type").(string) == client.AccessItemTypeUserGroup {
+		if !d.NewValueKnown(key+".parent") || d.Get(key+".parent").(string) != "" {
+			problems = append(problems, fmt.Sprintf("%s %d: parent can only be set for the NETWORK and HOST types", block, i))
+		}
+	}
+	return problems
+}
+
+// configuredChildren returns how many children the i-th block of the source
+// or destination sets in the configuration, and whether that is known. The
+// configuration is read directly, since a set of children that are not known
+// yet is read as empty from the diff.
+func configuredChildren(d *schema.ResourceDiff, block string, i int) (int, bool) {
+	config := d.GetRawConfig()
+	if !config.IsKnown() || config.IsNull() {
+		return 0, false
+	}
+	blocks := config.GetAttr(block)
+	if !blocks.IsKnown() || blocks.IsNull() || blocks.LengthInt() <= i {
+		return 0, false
+	}
+	children := blocks.Index(cty.NumberIntVal(int64(i))).GetAttr("children")
+	if !children.IsKnown() {
+		return 0, false
+	}
+	if children.IsNull() {
+		return 0, true
+	}
+	return children.LengthInt(), true
+}
@@ -0,0 +1,163 @@
+package openvpncloud
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+)
+
+func TestAccOpenvpncloudAccessGroup_basic(t *testing.T) {
+	resourceName := "openvpncloud_access_group.test"
+	name := acctest.RandomWithPrefix("tf-acc")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroy("openvpncloud_access_group", testAccAccessGroupExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAccessGroupConfig(name, `
+  source {
+    type     = "USER_GROUP"
+    children = [openvpncloud_user_group.test.id]
+  }
+  destination {
+    type     = "NETWORK"
+    children = [openvpncloud_network.test.id]
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccAccessGroupExists),
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "source.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "source.0.type", client.AccessItemTypeUserGroup),
+					resource.TestCheckResourceAttr(resourceName, "source.0.all_covered", "false"),
+					resource.TestCheckTypeSetElemAttrPair(resourceName, "source.0.children.*", "openvpncloud_user_group.test", "id"),
+					resource.TestCheckResourceAttr(resourceName, "destination.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair(resourceName, "destination.0.children.*", "openvpncloud_network.test", "id"),
+				),
+			},
+			{
+				Config: testAccAccessGroupConfig(name, `
+  source {
+    type        = "USER_GROUP"
+    all_covered = true
+  }
+  destination {
+    type     = "NETWORK"
+    children = [openvpncloud_network.test.id]
+  }
+  destination {
+    type        = "NETWORK"
+    parent      = openvpncloud_network.test.id
+    all_covered = true
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccAccessGroupExists),
+					resource.TestCheckResourceAttr(resourceName, "source.0.all_covered", "true"),
+					resource.TestCheckResourceAttr(resourceName, "source.0.children.#", "0"),
+					resource.TestCheckResourceAttr(resourceName, "destination.#", "2"),
+					resource.TestCheckResourceAttrPair(resourceName, "destination.1.parent", "openvpncloud_network.test", "id"),
+					resource.TestCheckResourceAttr(resourceName, "destination.1.all_covered", "true"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccAccessGroupConfig(name, `
+  source {
+    type        = "USER_GROUP"
+    all_covered = true
+  }
+  destination {
+    type     = "NETWORK"
+    children = [openvpncloud_network.test.id]
+  }
+`),
+				Check:              testAccDeleteOutOfBand(resourceName, testAccAccessGroupDelete),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccOpenvpncloudAccessGroup_validation(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-acc")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAccessGroupConfig(name, `
+  source {
+    type = "USER_GROUP"
+  }
+  destination {
+    type        = "NETWORK"
+    all_covered = true
+    children    = [openvpncloud_network.test.id]
+  }
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)source 0: children must be set unless all_covered is set.*destination 0: children must be empty when all_covered is set`),
+			},
+			{
+				Config: testAccAccessGroupConfig(name, `
+  source {
+    type        = "USER_GROUP"
+    all_covered = true
+  }
+  destination {
+    type        = "USER_GROUP"
+    parent      = openvpncloud_user_group.test.id
+    all_covered = true
+  }
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`destination 0: parent can only be set for the NETWORK and HOST types`),
+			},
+		},
+	})
+}
+
+func testAccAccessGroupExists(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) (bool, error) {
+	accessGroup, err := c.GetAccessGroupById(context.Background(), rs.Primary.ID)
+	return testAccFound(accessGroup != nil, err)
+}
+
+func testAccAccessGroupDelete(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) error {
+	return c.DeleteAccessGroup(context.Background(), rs.Primary.ID)
+}
+
+func testAccAccessGroupConfig(name string, blocks string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "openvpncloud_user_group" "test" {
+  name           = "%[1]s-group"
+  vpn_region_ids = [%[2]q]
+}
+
+resource "openvpncloud_network" "test" {
+  name = "%[1]s-network"
+  default_route {
+    value = "10.60.0.0/24"
+  }
+  default_connector {
+    name          = "%[1]s-connector"
+    vpn_region_id = %[2]q
+  }
+}
+
+resource "openvpncloud_access_group" "test" {
+  name = %[1]q
+%[3]s}
+`, name, testAccVpnRegionId(), blocks)
+}
@@ -59,7 +59,7 @@ func resourceConnector() *schema.Resource {
 }
 
 func resourceConnectorCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	name := d.Get("name").(string)
 	networkItemId := d.Get("network_item_id").(string)
@@ -71,22 +71,27 @@ func resourceConnectorCreate(ctx context.Context, d *schema.ResourceData, m inte
 		NetworkItemType: networkItemType,
 		VpnRegionId:     vpnRegionId,
 	}
-	conn, err := c.AddConnector(connector, networkItemId)
+	conn, err := c.AddConnector(ctx, connector, networkItemId)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	d.SetId(conn.Id)
-	return append(diags, diag.Diagnostic{
+	diags = append(diags, diag.Diagnostic{
 		Severity: diag.Warning,
 		Summary:  "Connector needs to be set up manually",
 		Detail:   "Terraform only creates the OpenVPN Cloud connector object, but additional manual steps are required to associate a host in your infrastructure with this connector. Go to https://openvpn.net/cloud-docs/connector/ for more information.",
 	})
+	return append(diags, resourceConnectorRead(ctx, d, m)...)
 }
 
 func resourceConnectorRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
-	connector, err := c.GetConnectorById(d.Id())
+	connector, err := c.GetConnectorById(ctx, d.Id())
+	if client.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
@@ -105,10 +110,10 @@ func resourceConnectorRead(ctx context.Context, d *schema.ResourceData, m interf
 }
 
 func resourceConnectorDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
-	err := c.DeleteConnector(d.Id(), d.Get("network_item_id").(string), d.Get("network_item_type").(string))
-	if err != nil {
+	err := c.DeleteConnector(ctx, d.Id(), d.Get("network_item_id").(string), d.Get("network_item_type").(string))
+	if err != nil && !client.IsNotFound(err) {
 		return append(diags, diag.FromErr(err)...)
 	}
 	return diags
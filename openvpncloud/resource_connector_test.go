@@ -0,0 +1,109 @@
+package openvpncloud
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client/cassette"
+)
+
+func TestAccOpenvpncloudConnector_basic(t *testing.T) {
+	resourceName := "openvpncloud_connector.test"
+	name := acctest.RandomWithPrefix("tf-acc")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroy("openvpncloud_connector", testAccConnectorExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccConnectorConfig(name, name+"-extra"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccConnectorExists),
+					resource.TestCheckResourceAttr(resourceName, "name", name+"-extra"),
+					resource.TestCheckResourceAttr(resourceName, "vpn_region_id", testAccVpnRegionId()),
+					resource.TestCheckResourceAttr(resourceName, "network_item_type", client.NetworkItemTypeNetwork),
+					resource.TestCheckResourceAttrPair(resourceName, "network_item_id", "openvpncloud_network.test", "id"),
+					resource.TestCheckResourceAttrSet(resourceName, "ip_v4_address"),
+					resource.TestCheckResourceAttrSet(resourceName, "ip_v6_address"),
+				),
+			},
+			{
+				Config: testAccConnectorConfig(name, name+"-renamed"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccConnectorExists),
+					resource.TestCheckResourceAttr(resourceName, "name", name+"-renamed"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:             testAccConnectorConfig(name, name+"-renamed"),
+				Check:              testAccDeleteOutOfBand(resourceName, testAccConnectorDelete),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccConnectorExists(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) (bool, error) {
+	connector, err := c.GetConnectorById(context.Background(), rs.Primary.ID)
+	return testAccFound(connector != nil, err)
+}
+
+func testAccConnectorDelete(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) error {
+	return c.DeleteConnector(context.Background(), rs.Primary.ID, rs.Primary.Attributes["network_item_id"], rs.Primary.Attributes["network_item_type"])
+}
+
+func testAccConnectorConfig(networkName string, connectorName string) string {
+	return testAccNetworkConfig(networkName, "Managed by Terraform", "10.0.0.0/18") + fmt.Sprintf(`
+resource "openvpncloud_connector" "test" {
+  name              = %q
+  vpn_region_id     = %q
+  network_item_type = "NETWORK"
+  network_item_id   = openvpncloud_network.test.id
+}
+`, connectorName, testAccVpnRegionId())
+}
+
+// TestResourceConnectorLifecycle replays the cassette in testdata/cassettes,
+// recorded against the fake API, to check that the addresses of a connector
+// are read back into its state.
+func TestResourceConnectorLifecycle(t *testing.T) {
+	ctx := context.Background()
+	c := cassette.NewClient(t, "resource_connector")
+	network, err := c.CreateNetwork(ctx, client.Network{
+		Name:           "tf-cassette-resource-connector",
+		InternetAccess: "LOCAL",
+		Routes:         []client.Route{{Type: client.RouteTypeIPV4, Value: "10.30.0.0/24"}},
+		Connectors:     []client.Connector{{Name: "tf-cassette-default-connector", VpnRegionId: "us-east-1"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.DeleteNetwork(ctx, network.Id)
+	r := resourceConnector()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":              "tf-cassette-connector",
+		"vpn_region_id":     "us-east-1",
+		"network_item_type": client.NetworkItemTypeNetwork,
+		"network_item_id":   network.Id,
+	})
+	if diags := r.CreateContext(ctx, d, c); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	if d.Id() == "" || d.Get("ip_v4_address") == "" || d.Get("ip_v6_address") == "" {
+		t.Errorf("expected the connector id and addresses to be set, got %q, %q and %q", d.Id(), d.Get("ip_v4_address"), d.Get("ip_v6_address"))
+	}
+	if diags := r.DeleteContext(ctx, d, c); diags.HasError() {
+		t.Fatalf("delete failed: %v", diags)
+	}
+}
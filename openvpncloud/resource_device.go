@@ -0,0 +1,136 @@
+package openvpncloud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+)
+
+func resourceDevice() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Use `openvpncloud_device` to create a device for an OpenVPN Cloud user.",
+		CreateContext: resourceDeviceCreate,
+		ReadContext:   resourceDeviceRead,
+		UpdateContext: resourceDeviceUpdate,
+		DeleteContext: resourceDeviceDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDeviceImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the user the device belongs to.",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringLenBetween(1, 32),
+				Description:  "The device name.",
+			},
+			"description": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "Managed by Terraform",
+				ValidateFunc: validation.StringLenBetween(1, 120),
+				Description:  "The device description. Defaults to `Managed by Terraform`.",
+			},
+			"ipv4_address": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IsIPv4Address,
+				Description:  "The IPv4 address reserved for the device. Assigned by OpenVPN Cloud if not set.",
+			},
+			"ipv6_address": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IsIPv6Address,
+				Description:  "The IPv6 address reserved for the device. Assigned by OpenVPN Cloud if not set.",
+			},
+		},
+	}
+}
+
+func resourceDeviceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	device, err := c.CreateDevice(ctx, d.Get("user_id").(string), resourceDataToDevice(d))
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	d.SetId(device.Id)
+	return append(diags, resourceDeviceRead(ctx, d, m)...)
+}
+
+func resourceDeviceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	device, err := c.GetDevice(ctx, d.Get("user_id").(string), d.Id())
+	if client.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if device == nil {
+		d.SetId("")
+		return diags
+	}
+	d.Set("name", device.Name)
+	d.Set("description", device.Description)
+	d.Set("ipv4_address", device.IPv4Address)
+	d.Set("ipv6_address", device.IPv6Address)
+	return diags
+}
+
+func resourceDeviceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	device := resourceDataToDevice(d)
+	device.Id = d.Id()
+	err := c.UpdateDevice(ctx, d.Get("user_id").(string), device)
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	return append(diags, resourceDeviceRead(ctx, d, m)...)
+}
+
+func resourceDeviceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	err := c.DeleteDevice(ctx, d.Get("user_id").(string), d.Id())
+	if err != nil && !client.IsNotFound(err) {
+		return append(diags, diag.FromErr(err)...)
+	}
+	return diags
+}
+
+// resourceDeviceImport splits an import ID of the form user_id/device_id,
+// since a device can only be found through its user.
+func resourceDeviceImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid device import ID %q: expected user_id/device_id", d.Id())
+	}
+	d.Set("user_id", parts[0])
+	d.SetId(parts[1])
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceDataToDevice(d *schema.ResourceData) client.Device {
+	return client.Device{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		IPv4Address: d.Get("ipv4_address").(string),
+		IPv6Address: d.Get("ipv6_address").(string),
+	}
+}
@@ -0,0 +1,107 @@
+package openvpncloud
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+)
+
+func TestAccOpenvpncloudDevice_basic(t *testing.T) {
+	resourceName := "openvpncloud_device.laptop"
+	username := acctest.RandomWithPrefix("tf-acc")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroy("openvpncloud_device", testAccDeviceExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDeviceConfig(username, "laptop", ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccDeviceExists),
+					testAccCheckExists("openvpncloud_device.phone", testAccDeviceExists),
+					resource.TestCheckResourceAttrPair(resourceName, "user_id", "openvpncloud_user.test", "id"),
+					resource.TestCheckResourceAttr(resourceName, "name", "laptop"),
+					resource.TestCheckResourceAttr(resourceName, "description", "Managed by Terraform"),
+					resource.TestCheckResourceAttrSet(resourceName, "ipv4_address"),
+					resource.TestCheckResourceAttrSet(resourceName, "ipv6_address"),
+				),
+			},
+			{
+				Config: testAccDeviceConfig(username, "work-laptop", "100.96.200.10"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccDeviceExists),
+					resource.TestCheckResourceAttr(resourceName, "name", "work-laptop"),
+					resource.TestCheckResourceAttr(resourceName, "ipv4_address", "100.96.200.10"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateIdFunc: testAccDeviceImportId(resourceName),
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:  resourceName,
+				ImportState:   true,
+				ImportStateId: "missing-user-id",
+				ExpectError:   regexp.MustCompile(`expected user_id/device_id`),
+			},
+			{
+				Config:             testAccDeviceConfig(username, "work-laptop", "100.96.200.10"),
+				Check:              testAccDeleteOutOfBand(resourceName, testAccDeviceDelete),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccDeviceExists(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) (bool, error) {
+	device, err := c.GetDevice(context.Background(), rs.Primary.Attributes["user_id"], rs.Primary.ID)
+	return testAccFound(device != nil, err)
+}
+
+func testAccDeviceDelete(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) error {
+	return c.DeleteDevice(context.Background(), rs.Primary.Attributes["user_id"], rs.Primary.ID)
+}
+
+func testAccDeviceImportId(name string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return "", fmt.Errorf("resource %s not found in state", name)
+		}
+		return rs.Primary.Attributes["user_id"] + "/" + rs.Primary.ID, nil
+	}
+}
+
+func testAccDeviceConfig(username string, name string, ipV4Address string) string {
+	address := ""
+	if ipV4Address != "" {
+		address = fmt.Sprintf("  ipv4_address = %q\n", ipV4Address)
+	}
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "openvpncloud_user" "test" {
+  username   = %[1]q
+  email      = "%[1]s@example.com"
+  first_name = "Jane"
+  last_name  = "Doe"
+}
+
+resource "openvpncloud_device" "laptop" {
+  user_id = openvpncloud_user.test.id
+  name    = %[2]q
+%[3]s}
+
+resource "openvpncloud_device" "phone" {
+  user_id     = openvpncloud_user.test.id
+  name        = "phone"
+  description = "Jane's phone"
+}
+`, username, name, address)
+}
@@ -49,7 +49,7 @@ func resourceDnsRecord() *schema.Resource {
 }
 
 func resourceDnsRecordCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	domain := d.Get("domain").(string)
 	ipV4Addresses := d.Get("ip_v4_addresses").([]interface{})
@@ -67,7 +67,7 @@ func resourceDnsRecordCreate(ctx context.Context, d *schema.ResourceData, m inte
 		IPV4Addresses: ipV4AddressesSlice,
 		IPV6Addresses: ipV6AddressesSlice,
 	}
-	dnsRecord, err := c.CreateDnsRecord(dr)
+	dnsRecord, err := c.CreateDnsRecord(ctx, dr)
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
@@ -76,10 +76,14 @@ func resourceDnsRecordCreate(ctx context.Context, d *schema.ResourceData, m inte
 }
 
 func resourceDnsRecordRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	recordId := d.Id()
-	r, err := c.GetDnsRecord(recordId)
+	r, err := c.GetDnsRecord(ctx, recordId)
+	if client.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
@@ -94,7 +98,7 @@ func resourceDnsRecordRead(ctx context.Context, d *schema.ResourceData, m interf
 }
 
 func resourceDnsRecordUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	_, domain := d.GetChange("domain")
 	_, ipV4Addresses := d.GetChange("ip_v4_addresses")
@@ -107,7 +111,7 @@ func resourceDnsRecordUpdate(ctx context.Context, d *schema.ResourceData, m inte
 		IPV4Addresses: ipV4AddressesSlice,
 		IPV6Addresses: ipV6AddressesSlice,
 	}
-	err := c.UpdateDnsRecord(dr)
+	err := c.UpdateDnsRecord(ctx, dr)
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
@@ -115,11 +119,11 @@ func resourceDnsRecordUpdate(ctx context.Context, d *schema.ResourceData, m inte
 }
 
 func resourceDnsRecordDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	routeId := d.Id()
-	err := c.DeleteDnsRecord(routeId)
-	if err != nil {
+	err := c.DeleteDnsRecord(ctx, routeId)
+	if err != nil && !client.IsNotFound(err) {
 		return append(diags, diag.FromErr(err)...)
 	}
 	return diags
@@ -0,0 +1,82 @@
+package openvpncloud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+)
+
+func TestAccOpenvpncloudDnsRecord_basic(t *testing.T) {
+	resourceName := "openvpncloud_dns_record.test"
+	domain := acctest.RandomWithPrefix("tf-acc") + ".example.com"
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroy("openvpncloud_dns_record", testAccDnsRecordExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDnsRecordConfig(domain, []string{"10.10.0.1"}, []string{"fd00::1"}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccDnsRecordExists),
+					resource.TestCheckResourceAttr(resourceName, "domain", domain),
+					resource.TestCheckResourceAttr(resourceName, "ip_v4_addresses.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "ip_v4_addresses.0", "10.10.0.1"),
+					resource.TestCheckResourceAttr(resourceName, "ip_v6_addresses.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "ip_v6_addresses.0", "fd00::1"),
+				),
+			},
+			{
+				Config: testAccDnsRecordConfig(domain, []string{"10.10.0.1", "10.10.0.2"}, []string{}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccDnsRecordExists),
+					resource.TestCheckResourceAttr(resourceName, "ip_v4_addresses.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "ip_v4_addresses.1", "10.10.0.2"),
+					resource.TestCheckResourceAttr(resourceName, "ip_v6_addresses.#", "0"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:             testAccDnsRecordConfig(domain, []string{"10.10.0.1", "10.10.0.2"}, []string{}),
+				Check:              testAccDeleteOutOfBand(resourceName, testAccDnsRecordDelete),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccDnsRecordExists(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) (bool, error) {
+	record, err := c.GetDnsRecord(context.Background(), rs.Primary.ID)
+	return testAccFound(record != nil, err)
+}
+
+func testAccDnsRecordDelete(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) error {
+	return c.DeleteDnsRecord(context.Background(), rs.Primary.ID)
+}
+
+func testAccDnsRecordConfig(domain string, ipV4Addresses []string, ipV6Addresses []string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "openvpncloud_dns_record" "test" {
+  domain          = %q
+  ip_v4_addresses = [%s]
+  ip_v6_addresses = [%s]
+}
+`, domain, testAccQuotedList(ipV4Addresses), testAccQuotedList(ipV6Addresses))
+}
+
+func testAccQuotedList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return strings.Join(quoted, ", ")
+}
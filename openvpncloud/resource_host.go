@@ -102,7 +102,7 @@ func resourceHost() *schema.Resource {
 }
 
 func resourceHostCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	var connectors []client.Connector
 	configConnectors := d.Get("connector").(*schema.Set)
@@ -118,22 +118,27 @@ func resourceHostCreate(ctx context.Context, d *schema.ResourceData, m interface
 		InternetAccess: d.Get("internet_access").(string),
 		Connectors:     connectors,
 	}
-	host, err := c.CreateHost(h)
+	host, err := c.CreateHost(ctx, h)
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
 	d.SetId(host.Id)
-	return append(diags, diag.Diagnostic{
+	diags = append(diags, diag.Diagnostic{
 		Severity: diag.Warning,
 		Summary:  "The connector for this host needs to be set up manually",
 		Detail:   "Terraform only creates the OpenVPN Cloud connector object for this host, but additional manual steps are required to associate a host in your infrastructure with this connector. Go to https://openvpn.net/cloud-docs/connector/ for more information.",
 	})
+	return append(diags, resourceHostRead(ctx, d, m)...)
 }
 
 func resourceHostRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
-	host, err := c.GetHostById(d.Id())
+	host, err := c.GetHostById(ctx, d.Id())
+	if client.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
@@ -165,7 +170,7 @@ func resourceHostRead(ctx context.Context, d *schema.ResourceData, m interface{}
 }
 
 func resourceHostUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	if d.HasChange("connector") {
 		old, new := d.GetChange("connector")
@@ -178,14 +183,14 @@ func resourceHostUpdate(ctx context.Context, d *schema.ResourceData, m interface
 				VpnRegionId:     newSet.List()[0].(map[string]interface{})["vpn_region_id"].(string),
 				NetworkItemType: client.NetworkItemTypeHost,
 			}
-			_, err := c.AddConnector(newConnector, d.Id())
+			_, err := c.AddConnector(ctx, newConnector, d.Id())
 			if err != nil {
 				return append(diags, diag.FromErr(err)...)
 			}
 		} else {
 			for _, o := range oldSet.List() {
 				if !newSet.Contains(o) {
-					err := c.DeleteConnector(o.(map[string]interface{})["id"].(string), d.Id(), client.NetworkItemTypeHost)
+					err := c.DeleteConnector(ctx, o.(map[string]interface{})["id"].(string), d.Id(), client.NetworkItemTypeHost)
 					if err != nil {
 						diags = append(diags, diag.FromErr(err)...)
 					}
@@ -198,7 +203,7 @@ func resourceHostUpdate(ctx context.Context, d *schema.ResourceData, m interface
 						VpnRegionId:     n.(map[string]interface{})["vpn_region_id"].(string),
 						NetworkItemType: client.NetworkItemTypeHost,
 					}
-					_, err := c.AddConnector(newConnector, d.Id())
+					_, err := c.AddConnector(ctx, newConnector, d.Id())
 					if err != nil {
 						diags = append(diags, diag.FromErr(err)...)
 					}
@@ -210,7 +215,7 @@ func resourceHostUpdate(ctx context.Context, d *schema.ResourceData, m interface
 		_, newName := d.GetChange("name")
 		_, newDescription := d.GetChange("description")
 		_, newAccess := d.GetChange("internet_access")
-		err := c.UpdateHost(client.Host{
+		err := c.UpdateHost(ctx, client.Host{
 			Id:             d.Id(),
 			Name:           newName.(string),
 			Description:    newDescription.(string),
@@ -224,11 +229,11 @@ func resourceHostUpdate(ctx context.Context, d *schema.ResourceData, m interface
 }
 
 func resourceHostDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	hostId := d.Id()
-	err := c.DeleteHost(hostId)
-	if err != nil {
+	err := c.DeleteHost(ctx, hostId)
+	if err != nil && !client.IsNotFound(err) {
 		return append(diags, diag.FromErr(err)...)
 	}
 	return diags
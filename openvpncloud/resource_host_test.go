@@ -0,0 +1,169 @@
+package openvpncloud
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client/fake"
+)
+
+func TestAccOpenvpncloudHost_basic(t *testing.T) {
+	resourceName := "openvpncloud_host.test"
+	name := acctest.RandomWithPrefix("tf-acc")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroy("openvpncloud_host", testAccHostExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccHostConfig(name, "Created by Terraform", name+"-a"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccHostExists),
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "description", "Created by Terraform"),
+					resource.TestCheckResourceAttr(resourceName, "internet_access", client.InternetAccessLocal),
+					resource.TestCheckResourceAttr(resourceName, "connector.#", "1"),
+				),
+			},
+			{
+				Config: testAccHostConfig(name, "Updated by Terraform", name+"-a", name+"-b"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccHostExists),
+					resource.TestCheckResourceAttr(resourceName, "description", "Updated by Terraform"),
+					resource.TestCheckResourceAttr(resourceName, "connector.#", "2"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:             testAccHostConfig(name, "Updated by Terraform", name+"-a", name+"-b"),
+				Check:              testAccDeleteOutOfBand(resourceName, testAccHostDelete),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccHostExists(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) (bool, error) {
+	host, err := c.GetHostById(context.Background(), rs.Primary.ID)
+	return testAccFound(host != nil, err)
+}
+
+func testAccHostDelete(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) error {
+	return c.DeleteHost(context.Background(), rs.Primary.ID)
+}
+
+func testAccHostConfig(name string, description string, connectorNames ...string) string {
+	connectors := ""
+	for _, connectorName := range connectorNames {
+		connectors += fmt.Sprintf(`
+  connector {
+    name          = %q
+    vpn_region_id = %q
+  }`, connectorName, testAccVpnRegionId())
+	}
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "openvpncloud_host" "test" {
+  name        = %q
+  description = %q%s
+}
+`, name, description, connectors)
+}
+
+// TestResourceHostUpdateConnectors checks which connectors an update adds and
+// removes, and that the state reflects the connectors of the host afterwards.
+func TestResourceHostUpdateConnectors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		before      []string
+		after       []string
+		description string
+		kept        []string
+	}{
+		"add":       {before: []string{"a"}, after: []string{"a", "b"}, kept: []string{"a"}},
+		"remove":    {before: []string{"a", "b"}, after: []string{"b"}, kept: []string{"b"}},
+		"replace":   {before: []string{"a"}, after: []string{"b"}},
+		"unchanged": {before: []string{"a", "b"}, after: []string{"a", "b"}, description: "Updated by Terraform", kept: []string{"a", "b"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			c := fake.NewClient()
+			r := resourceHost()
+			state := testResourceApply(t, r, nil, testHostRaw("", tc.before), c)
+			before := testHostConnectorIds(t, c, state.ID)
+			state = testResourceApply(t, r, state, testHostRaw(tc.description, tc.after), c)
+			after := testHostConnectorIds(t, c, state.ID)
+			if len(after) != len(tc.after) {
+				t.Errorf("expected the connectors %v, got %v", tc.after, after)
+			}
+			for _, connectorName := range tc.after {
+				if after[connectorName] == "" {
+					t.Errorf("expected the connector %q to be added, got %v", connectorName, after)
+				}
+			}
+			for _, connectorName := range tc.kept {
+				if after[connectorName] != before[connectorName] {
+					t.Errorf("expected the connector %q to be kept, its id went from %q to %q", connectorName, before[connectorName], after[connectorName])
+				}
+			}
+			host, err := c.GetHostById(ctx, state.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.description != "" && host.Description != tc.description {
+				t.Errorf("expected the description to be %q, got %q", tc.description, host.Description)
+			}
+			connectors := r.Data(state).Get("connector").(*schema.Set).List()
+			if len(connectors) != len(host.Connectors) {
+				t.Fatalf("expected %d connectors in the state, got %d", len(host.Connectors), len(connectors))
+			}
+			for _, conn := range connectors {
+				connector := conn.(map[string]interface{})
+				connectorName := connector["name"].(string)
+				if connector["id"] != after[connectorName] || connector["ip_v4_address"] == "" || connector["network_item_id"] != host.Id {
+					t.Errorf("expected the state of the connector %q to match the API, got %v", connectorName, connector)
+				}
+			}
+		})
+	}
+}
+
+func testHostRaw(description string, connectorNames []string) map[string]interface{} {
+	connectors := []interface{}{}
+	for _, connectorName := range connectorNames {
+		connectors = append(connectors, map[string]interface{}{
+			"name":          connectorName,
+			"vpn_region_id": fake.DefaultVpnRegions[0].Id,
+		})
+	}
+	raw := map[string]interface{}{
+		"name":      "tf-unit-host",
+		"connector": connectors,
+	}
+	if description != "" {
+		raw["description"] = description
+	}
+	return raw
+}
+
+// testHostConnectorIds returns the ids of the connectors of a host, by name.
+func testHostConnectorIds(t *testing.T, c client.OpenVPNCloudAPI, hostId string) map[string]string {
+	t.Helper()
+	host, err := c.GetHostById(context.Background(), hostId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := map[string]string{}
+	for _, conn := range host.Connectors {
+		ids[conn.Name] = conn.Id
+	}
+	return ids
+}
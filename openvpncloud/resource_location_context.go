@@ -0,0 +1,270 @@
+package openvpncloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+)
+
+func resourceLocationContext() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Use `openvpncloud_location_context` to allow or deny the connections of user groups based on the address and country they connect from.",
+		CreateContext: resourceLocationContextCreate,
+		ReadContext:   resourceLocationContextRead,
+		UpdateContext: resourceLocationContextUpdate,
+		DeleteContext: resourceLocationContextDelete,
+		CustomizeDiff: resourceLocationContextCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringLenBetween(1, 40),
+				Description:  "The name of the location context.",
+			},
+			"description": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "Managed by Terraform",
+				ValidateFunc: validation.StringLenBetween(1, 120),
+				Description:  "The description for the UI. Defaults to `Managed by Terraform`.",
+			},
+			"user_groups_ids": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotWhiteSpace,
+				},
+				Description: "The IDs of the user groups the location context applies to.",
+			},
+			"ip_check": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Allows or denies connections from a list of subnets.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"allowed": {
+							Type:        schema.TypeBool,
+							Required:    true,
+							Description: "Whether connections from the subnets are allowed or denied.",
+						},
+						"ip": {
+							Type:        schema.TypeList,
+							Required:    true,
+							MinItems:    1,
+							Description: "A subnet connections come from. Can be defined more than once.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"ip": {
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validateNetworkCIDR,
+										Description:  "The IPv4 or IPv6 subnet in CIDR notation, without host bits, such as `203.0.113.0/24`.",
+									},
+									"description": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										Default:      "Managed by Terraform",
+										ValidateFunc: validation.StringLenBetween(1, 120),
+										Description:  "The description of the subnet. Defaults to `Managed by Terraform`.",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"country_check": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Allows or denies connections from a list of countries.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"allowed": {
+							Type:        schema.TypeBool,
+							Required:    true,
+							Description: "Whether connections from the countries are allowed or denied.",
+						},
+						"countries": {
+							Type:     schema.TypeSet,
+							Required: true,
+							MinItems: 1,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validateCountryCode,
+							},
+							Description: "The upper case ISO 3166-1 alpha-2 codes of the countries, in the format of the `country_iso` of `openvpncloud_vpn_region`.",
+						},
+					},
+				},
+			},
+			"default_check": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				MaxItems:    1,
+				Description: "The policy for the connections that no other check matches.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"allowed": {
+							Type:        schema.TypeBool,
+							Required:    true,
+							Description: "Whether the connections are allowed or denied.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceLocationContextCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	lc := resourceDataToLocationContext(d)
+	locationContext, err := c.CreateLocationContext(ctx, lc)
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	d.SetId(locationContext.Id)
+	return append(diags, resourceLocationContextRead(ctx, d, m)...)
+}
+
+func resourceLocationContextRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	locationContext, err := c.GetLocationContextById(ctx, d.Id())
+	if client.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if locationContext == nil {
+		d.SetId("")
+		return diags
+	}
+	d.Set("name", locationContext.Name)
+	d.Set("description", locationContext.Description)
+	d.Set("user_groups_ids", locationContext.UserGroupsIds)
+	d.Set("ip_check", flattenIpCheck(locationContext.IpCheck))
+	d.Set("country_check", flattenCountryCheck(locationContext.CountryCheck))
+	d.Set("default_check", []interface{}{map[string]interface{}{"allowed": locationContext.DefaultCheck.Allowed}})
+	return diags
+}
+
+func resourceLocationContextUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	lc := resourceDataToLocationContext(d)
+	lc.Id = d.Id()
+	err := c.UpdateLocationContext(ctx, lc)
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	return append(diags, resourceLocationContextRead(ctx, d, m)...)
+}
+
+func resourceLocationContextDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	err := c.DeleteLocationContext(ctx, d.Id())
+	if err != nil && !client.IsNotFound(err) {
+		return append(diags, diag.FromErr(err)...)
+	}
+	return diags
+}
+
+func resourceDataToLocationContext(d *schema.ResourceData) client.LocationContext {
+	lc := client.LocationContext{
+		Name:          d.Get("name").(string),
+		Description:   d.Get("description").(string),
+		UserGroupsIds: getAddressesSlice(d.Get("user_groups_ids").(*schema.Set).List()),
+		DefaultCheck: client.DefaultCheck{
+			Allowed: d.Get("default_check.0.allowed").(bool),
+		},
+	}
+	if ipChecks := d.Get("ip_check").([]interface{}); len(ipChecks) > 0 {
+		ipCheck := ipChecks[0].(map[string]interface{})
+		lc.IpCheck = &client.IpCheck{Allowed: ipCheck["allowed"].(bool)}
+		for _, i := range ipCheck["ip"].([]interface{}) {
+			ip := i.(map[string]interface{})
+			lc.IpCheck.Ips = append(lc.IpCheck.Ips, client.Ip{
+				Ip:          ip["ip"].(string),
+				Description: ip["description"].(string),
+			})
+		}
+	}
+	if countryChecks := d.Get("country_check").([]interface{}); len(countryChecks) > 0 {
+		countryCheck := countryChecks[0].(map[string]interface{})
+		lc.CountryCheck = &client.CountryCheck{
+			Allowed:   countryCheck["allowed"].(bool),
+			Countries: getAddressesSlice(countryCheck["countries"].(*schema.Set).List()),
+		}
+	}
+	return lc
+}
+
+func flattenIpCheck(ipCheck *client.IpCheck) []interface{} {
+	if ipCheck == nil {
+		return nil
+	}
+	ips := make([]interface{}, 0, len(ipCheck.Ips))
+	for _, ip := range ipCheck.Ips {
+		ips = append(ips, map[string]interface{}{
+			"ip":          ip.Ip,
+			"description": ip.Description,
+		})
+	}
+	return []interface{}{map[string]interface{}{
+		"allowed": ipCheck.Allowed,
+		"ip":      ips,
+	}}
+}
+
+func flattenCountryCheck(countryCheck *client.CountryCheck) []interface{} {
+	if countryCheck == nil {
+		return nil
+	}
+	return []interface{}{map[string]interface{}{
+		"allowed":   countryCheck.Allowed,
+		"countries": countryCheck.Countries,
+	}}
+}
+
+// resourceLocationContextCustomizeDiff checks that every user group exists, so
+// that a mistyped group is reported by its ID when planning rather than as a
+// rejected request when applying. Groups that are not known yet, such as the
+// ones created in the same run, are left to the API.
+func resourceLocationContextCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("user_groups_ids") || !d.NewValueKnown("user_groups_ids") {
+		return nil
+	}
+	c := m.(client.OpenVPNCloudAPI)
+	return validateUserGroupIds(ctx, c, getAddressesSlice(d.Get("user_groups_ids").(*schema.Set).List()))
+}
+
+// validateUserGroupIds checks that every user group exists.
+func validateUserGroupIds(ctx context.Context, c client.OpenVPNCloudAPI, userGroupIds []string) error {
+	for _, userGroupId := range userGroupIds {
+		userGroup, err := c.GetUserGroupById(ctx, userGroupId)
+		if err != nil {
+			return err
+		}
+		if userGroup == nil {
+			return fmt.Errorf("user group %s does not exist", userGroupId)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package openvpncloud
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+)
+
+func TestAccOpenvpncloudLocationContext_basic(t *testing.T) {
+	resourceName := "openvpncloud_location_context.test"
+	name := acctest.RandomWithPrefix("tf-acc")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroy("openvpncloud_location_context", testAccLocationContextExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLocationContextConfig(name, "203.0.113.0/24", "US", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccLocationContextExists),
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "description", "Managed by Terraform"),
+					resource.TestCheckResourceAttrPair(resourceName, "user_groups_ids.0", "openvpncloud_user_group.test", "id"),
+					resource.TestCheckResourceAttr(resourceName, "ip_check.0.allowed", "true"),
+					resource.TestCheckResourceAttr(resourceName, "ip_check.0.ip.0.ip", "203.0.113.0/24"),
+					resource.TestCheckResourceAttr(resourceName, "country_check.0.allowed", "true"),
+					resource.TestCheckResourceAttr(resourceName, "country_check.0.countries.#", "1"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "country_check.0.countries.*", "US"),
+					resource.TestCheckResourceAttr(resourceName, "default_check.0.allowed", "false"),
+				),
+			},
+			{
+				Config: testAccLocationContextConfig(name+"-renamed", "2001:db8::/32", "CA", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccLocationContextExists),
+					resource.TestCheckResourceAttr(resourceName, "name", name+"-renamed"),
+					resource.TestCheckResourceAttr(resourceName, "ip_check.0.ip.0.ip", "2001:db8::/32"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "country_check.0.countries.*", "CA"),
+					resource.TestCheckResourceAttr(resourceName, "default_check.0.allowed", "true"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:             testAccLocationContextConfig(name+"-renamed", "2001:db8::/32", "CA", true),
+				Check:              testAccDeleteOutOfBand(resourceName, testAccLocationContextDelete),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccOpenvpncloudLocationContext_validation(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-acc")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccLocationContextConfig(name, "203.0.113.7/24", "US", false),
+				ExpectError: regexp.MustCompile(`to be a network address without host bits, such as 203.0.113.0/24`),
+			},
+			{
+				Config:      testAccLocationContextConfig(name, "203.0.113.0/24", "us", false),
+				ExpectError: regexp.MustCompile(`to be an upper case ISO 3166-1 alpha-2 country code`),
+			},
+			{
+				Config:      testAccLocationContextConfig(name, "203.0.113.0/24", "XX", false),
+				ExpectError: regexp.MustCompile(`to be an upper case ISO 3166-1 alpha-2 country code`),
+			},
+			{
+				Config:      strings.Replace(testAccLocationContextConfig(name, "203.0.113.0/24", "US", false), "[openvpncloud_user_group.test.id]", `["tf-acc-missing-group"]`, 1),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`user group tf-acc-missing-group does not exist`),
+			},
+		},
+	})
+}
+
+func testAccLocationContextExists(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) (bool, error) {
+	locationContext, err := c.GetLocationContextById(context.Background(), rs.Primary.ID)
+	return testAccFound(locationContext != nil, err)
+}
+
+func testAccLocationContextDelete(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) error {
+	return c.DeleteLocationContext(context.Background(), rs.Primary.ID)
+}
+
+func testAccLocationContextConfig(name string, ip string, country string, defaultAllowed bool) string {
+	return testAccUserGroupConfig(name, testAccVpnRegionId(), client.UserGroupInternetAccessSplitTunnelOn, 3) + fmt.Sprintf(`
+resource "openvpncloud_location_context" "test" {
+  name            = %q
+  user_groups_ids = [openvpncloud_user_group.test.id]
+
+  ip_check {
+    allowed = true
+    ip {
+      ip = %q
+    }
+  }
+
+  country_check {
+    allowed   = true
+    countries = [%q]
+  }
+
+  default_check {
+    allowed = %t
+  }
+}
+`, name, ip, country, defaultAllowed)
+}
@@ -130,7 +130,7 @@ func resourceNetwork() *schema.Resource {
 }
 
 func resourceNetworkCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	configConnector := d.Get("default_connector").([]interface{})[0].(map[string]interface{})
 	connectors := []client.Connector{
@@ -146,13 +146,13 @@ func resourceNetworkCreate(ctx context.Context, d *schema.ResourceData, m interf
 		InternetAccess: d.Get("internet_access").(string),
 		Connectors:     connectors,
 	}
-	network, err := c.CreateNetwork(n)
+	network, err := c.CreateNetwork(ctx, n)
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
 	d.SetId(network.Id)
 	configRoute := d.Get("default_route").([]interface{})[0].(map[string]interface{})
-	defaultRoute, err := c.CreateRoute(network.Id, client.Route{
+	defaultRoute, err := c.CreateRoute(ctx, network.Id, client.Route{
 		Type:  configRoute["type"].(string),
 		Value: configRoute["value"].(string),
 	})
@@ -164,17 +164,22 @@ func resourceNetworkCreate(ctx context.Context, d *schema.ResourceData, m interf
 		"id": defaultRoute.Id,
 	}
 	d.Set("default_route", defaultRouteWithIdSlice)
-	return append(diags, diag.Diagnostic{
+	diags = append(diags, diag.Diagnostic{
 		Severity: diag.Warning,
 		Summary:  "The default connector for this network needs to be set up manually",
 		Detail:   "Terraform only creates the OpenVPN Cloud default connector object for this network, but additional manual steps are required to associate a host in your infrastructure with this connector. Go to https://openvpn.net/cloud-docs/connector/ for more information.",
 	})
+	return append(diags, resourceNetworkRead(ctx, d, m)...)
 }
 
 func resourceNetworkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
-	network, err := c.GetNetworkById(d.Id())
+	network, err := c.GetNetworkById(ctx, d.Id())
+	if client.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
@@ -190,7 +195,7 @@ func resourceNetworkRead(ctx context.Context, d *schema.ResourceData, m interfac
 	if len(d.Get("default_connector").([]interface{})) > 0 {
 		configConnector := d.Get("default_connector").([]interface{})[0].(map[string]interface{})
 		connectorName := configConnector["name"].(string)
-		networkConnectors, err := c.GetConnectorsForNetwork(network.Id)
+		networkConnectors, err := c.GetConnectorsForNetwork(ctx, network.Id)
 		if err != nil {
 			return append(diags, diag.FromErr(err)...)
 		}
@@ -201,7 +206,11 @@ func resourceNetworkRead(ctx context.Context, d *schema.ResourceData, m interfac
 	}
 	if len(d.Get("default_route").([]interface{})) > 0 {
 		configRoute := d.Get("default_route").([]interface{})[0].(map[string]interface{})
-		route, err := c.GetNetworkRoute(d.Id(), configRoute["id"].(string))
+		route, err := c.GetNetworkRoute(ctx, d.Id(), configRoute["id"].(string))
+		if client.IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
 		if err != nil {
 			return append(diags, diag.FromErr(err)...)
 		}
@@ -226,7 +235,7 @@ func resourceNetworkRead(ctx context.Context, d *schema.ResourceData, m interfac
 }
 
 func resourceNetworkUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	if d.HasChange("default_connector") {
 		old, new := d.GetChange("default_connector")
@@ -239,7 +248,7 @@ func resourceNetworkUpdate(ctx context.Context, d *schema.ResourceData, m interf
 				VpnRegionId:     newSlice[0].(map[string]interface{})["vpn_region_id"].(string),
 				NetworkItemType: client.NetworkItemTypeNetwork,
 			}
-			_, err := c.AddConnector(newConnector, d.Id())
+			_, err := c.AddConnector(ctx, newConnector, d.Id())
 			if err != nil {
 				return append(diags, diag.FromErr(err)...)
 			}
@@ -252,13 +261,13 @@ func resourceNetworkUpdate(ctx context.Context, d *schema.ResourceData, m interf
 					VpnRegionId:     newMap["vpn_region_id"].(string),
 					NetworkItemType: client.NetworkItemTypeNetwork,
 				}
-				_, err := c.AddConnector(newConnector, d.Id())
+				_, err := c.AddConnector(ctx, newConnector, d.Id())
 				if err != nil {
 					return append(diags, diag.FromErr(err)...)
 				}
 				if len(oldMap["id"].(string)) > 0 {
 					// This can sometimes happen when importing the resource
-					err = c.DeleteConnector(oldMap["id"].(string), d.Id(), oldMap["network_item_type"].(string))
+					err = c.DeleteConnector(ctx, oldMap["id"].(string), d.Id(), oldMap["network_item_type"].(string))
 					if err != nil {
 						return append(diags, diag.FromErr(err)...)
 					}
@@ -279,7 +288,7 @@ func resourceNetworkUpdate(ctx context.Context, d *schema.ResourceData, m interf
 				Type:  routeType.(string),
 				Value: routeValue.(string),
 			}
-			defaultRoute, err := c.CreateRoute(d.Id(), route)
+			defaultRoute, err := c.CreateRoute(ctx, d.Id(), route)
 			if err != nil {
 				return append(diags, diag.FromErr(err)...)
 			}
@@ -301,7 +310,7 @@ func resourceNetworkUpdate(ctx context.Context, d *schema.ResourceData, m interf
 				Type:  routeType.(string),
 				Value: routeValue.(string),
 			}
-			err := c.UpdateRoute(d.Id(), route)
+			err := c.UpdateRoute(ctx, d.Id(), route)
 			if err != nil {
 				diags = append(diags, diag.FromErr(err)...)
 			}
@@ -312,7 +321,7 @@ func resourceNetworkUpdate(ctx context.Context, d *schema.ResourceData, m interf
 		_, newDescription := d.GetChange("description")
 		_, newEgress := d.GetChange("egress")
 		_, newAccess := d.GetChange("internet_access")
-		err := c.UpdateNetwork(client.Network{
+		err := c.UpdateNetwork(ctx, client.Network{
 			Id:             d.Id(),
 			Name:           newName.(string),
 			Description:    newDescription.(string),
@@ -327,11 +336,11 @@ func resourceNetworkUpdate(ctx context.Context, d *schema.ResourceData, m interf
 }
 
 func resourceNetworkDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	networkId := d.Id()
-	err := c.DeleteNetwork(networkId)
-	if err != nil {
+	err := c.DeleteNetwork(ctx, networkId)
+	if err != nil && !client.IsNotFound(err) {
 		return append(diags, diag.FromErr(err)...)
 	}
 	return diags
@@ -0,0 +1,161 @@
+package openvpncloud
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client/fake"
+)
+
+func TestAccOpenvpncloudNetwork_basic(t *testing.T) {
+	resourceName := "openvpncloud_network.test"
+	name := acctest.RandomWithPrefix("tf-acc")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroy("openvpncloud_network", testAccNetworkExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNetworkConfig(name, "Created by Terraform", "10.0.0.0/18"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccNetworkExists),
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "description", "Created by Terraform"),
+					resource.TestCheckResourceAttr(resourceName, "egress", "true"),
+					resource.TestCheckResourceAttr(resourceName, "internet_access", client.InternetAccessLocal),
+					resource.TestCheckResourceAttr(resourceName, "default_route.0.type", client.RouteTypeIPV4),
+					resource.TestCheckResourceAttr(resourceName, "default_route.0.value", "10.0.0.0/18"),
+					resource.TestCheckResourceAttrSet(resourceName, "default_route.0.id"),
+					resource.TestCheckResourceAttr(resourceName, "default_connector.0.name", name+"-connector"),
+					resource.TestCheckResourceAttr(resourceName, "default_connector.0.vpn_region_id", testAccVpnRegionId()),
+					resource.TestCheckResourceAttrSet(resourceName, "default_connector.0.id"),
+				),
+			},
+			{
+				Config: testAccNetworkConfig(name+"-updated", "Updated by Terraform", "10.0.0.0/18"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccNetworkExists),
+					resource.TestCheckResourceAttr(resourceName, "name", name+"-updated"),
+					resource.TestCheckResourceAttr(resourceName, "description", "Updated by Terraform"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The default route and connector are only tracked once they
+				// are part of the configuration.
+				ImportStateVerifyIgnore: []string{"default_route", "default_connector"},
+			},
+			{
+				Config:             testAccNetworkConfig(name+"-updated", "Updated by Terraform", "10.0.0.0/18"),
+				Check:              testAccDeleteOutOfBand(resourceName, testAccNetworkDelete),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccNetworkExists(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) (bool, error) {
+	network, err := c.GetNetworkById(context.Background(), rs.Primary.ID)
+	return testAccFound(network != nil, err)
+}
+
+func testAccNetworkDelete(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) error {
+	return c.DeleteNetwork(context.Background(), rs.Primary.ID)
+}
+
+func testAccNetworkConfig(name string, description string, routeValue string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "openvpncloud_network" "test" {
+  name        = %[1]q
+  description = %[2]q
+  default_route {
+    value = %[3]q
+  }
+  default_connector {
+    name          = "%[1]s-connector"
+    vpn_region_id = %[4]q
+  }
+}
+`, name, description, routeValue, testAccVpnRegionId())
+}
+
+// TestResourceNetworkUpdate checks that an update replaces the default
+// connector only when its name or region changes, updates the default route in
+// place, and leaves the state matching the network.
+func TestResourceNetworkUpdate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		description   string
+		connectorName string
+		vpnRegionId   string
+		routeValue    string
+		replaced      bool
+	}{
+		"description":      {description: "Updated by Terraform", connectorName: "tf-unit-connector", vpnRegionId: fake.DefaultVpnRegions[0].Id, routeValue: "10.0.0.0/24"},
+		"connector name":   {connectorName: "tf-unit-connector-renamed", vpnRegionId: fake.DefaultVpnRegions[0].Id, routeValue: "10.0.0.0/24", replaced: true},
+		"connector region": {connectorName: "tf-unit-connector", vpnRegionId: fake.DefaultVpnRegions[1].Id, routeValue: "10.0.0.0/24", replaced: true},
+		"route value":      {connectorName: "tf-unit-connector", vpnRegionId: fake.DefaultVpnRegions[0].Id, routeValue: "10.1.0.0/24"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			c := fake.NewClient()
+			r := resourceNetwork()
+			state := testResourceApply(t, r, nil, testNetworkRaw("", "tf-unit-connector", fake.DefaultVpnRegions[0].Id, "10.0.0.0/24"), c)
+			before := r.Data(state)
+			state = testResourceApply(t, r, state, testNetworkRaw(tc.description, tc.connectorName, tc.vpnRegionId, tc.routeValue), c)
+			after := r.Data(state)
+			connectors, err := c.GetConnectorsForNetwork(ctx, state.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(connectors) != 1 || connectors[0].Name != tc.connectorName || connectors[0].VpnRegionId != tc.vpnRegionId {
+				t.Fatalf("expected a single connector %q in %q, got %+v", tc.connectorName, tc.vpnRegionId, connectors)
+			}
+			if replaced := connectors[0].Id != before.Get("default_connector.0.id"); replaced != tc.replaced {
+				t.Errorf("expected the connector to be replaced: %t, got %t", tc.replaced, replaced)
+			}
+			if after.Get("default_connector.0.id") != connectors[0].Id || after.Get("default_connector.0.ip_v4_address") != connectors[0].IPv4Address {
+				t.Errorf("expected the default connector in the state to match %+v, got %v", connectors[0], after.Get("default_connector"))
+			}
+			routes, err := c.GetRoutes(ctx, state.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(routes) != 1 || routes[0].Id != before.Get("default_route.0.id") || routes[0].Subnet != tc.routeValue {
+				t.Errorf("expected the default route to be updated in place to %q, got %+v", tc.routeValue, routes)
+			}
+			if after.Get("default_route.0.value") != tc.routeValue {
+				t.Errorf("expected the default route in the state to be %q, got %v", tc.routeValue, after.Get("default_route"))
+			}
+			network, err := c.GetNetworkById(ctx, state.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.description != "" && (network.Description != tc.description || after.Get("description") != tc.description) {
+				t.Errorf("expected the description to be %q, got %q in the API and %q in the state", tc.description, network.Description, after.Get("description"))
+			}
+		})
+	}
+}
+
+func testNetworkRaw(description string, connectorName string, vpnRegionId string, routeValue string) map[string]interface{} {
+	raw := map[string]interface{}{
+		"name": "tf-unit-network",
+		"default_route": []interface{}{
+			map[string]interface{}{"value": routeValue},
+		},
+		"default_connector": []interface{}{
+			map[string]interface{}{"name": connectorName, "vpn_region_id": vpnRegionId},
+		},
+	}
+	if description != "" {
+		raw["description"] = description
+	}
+	return raw
+}
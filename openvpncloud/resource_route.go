@@ -43,7 +43,7 @@ func resourceRoute() *schema.Resource {
 }
 
 func resourceRouteCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	networkItemId := d.Get("network_item_id").(string)
 	routeType := d.Get("type").(string)
@@ -52,7 +52,7 @@ func resourceRouteCreate(ctx context.Context, d *schema.ResourceData, m interfac
 		Type:  routeType,
 		Value: routeValue,
 	}
-	route, err := c.CreateRoute(networkItemId, r)
+	route, err := c.CreateRoute(ctx, networkItemId, r)
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
@@ -66,10 +66,14 @@ func resourceRouteCreate(ctx context.Context, d *schema.ResourceData, m interfac
 }
 
 func resourceRouteRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	routeId := d.Id()
-	r, err := c.GetRouteById(routeId)
+	r, err := c.GetRouteById(ctx, routeId)
+	if client.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
@@ -80,7 +84,7 @@ func resourceRouteRead(ctx context.Context, d *schema.ResourceData, m interface{
 		if r.Type == client.RouteTypeIPV4 || r.Type == client.RouteTypeIPV6 {
 			d.Set("value", r.Subnet)
 		} else if r.Type == client.RouteTypeDomain {
-			d.Set("resourceRouteRead", r.Domain)
+			d.Set("value", r.Domain)
 		}
 		d.Set("network_item_id", r.NetworkItemId)
 	}
@@ -88,12 +92,12 @@ func resourceRouteRead(ctx context.Context, d *schema.ResourceData, m interface{
 }
 
 func resourceRouteDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	routeId := d.Id()
 	networkItemId := d.Get("network_item_id").(string)
-	err := c.DeleteRoute(networkItemId, routeId)
-	if err != nil {
+	err := c.DeleteRoute(ctx, networkItemId, routeId)
+	if err != nil && !client.IsNotFound(err) {
 		return append(diags, diag.FromErr(err)...)
 	}
 	return diags
@@ -14,6 +14,7 @@ func resourceUser() *schema.Resource {
 		Description:   "Use `openvpncloud_user` to create an OpenVPN Cloud user.",
 		CreateContext: resourceUserCreate,
 		ReadContext:   resourceUserRead,
+		UpdateContext: resourceUserUpdate,
 		DeleteContext: resourceUserDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -29,38 +30,58 @@ func resourceUser() *schema.Resource {
 			"email": {
 				Type:         schema.TypeString,
 				Required:     true,
-				ForceNew:     true,
 				ValidateFunc: validation.StringLenBetween(1, 120),
 				Description:  "An invitation to OpenVPN cloud account will be sent to this email. It will include an initial password and a VPN setup guide.",
 			},
 			"first_name": {
 				Type:         schema.TypeString,
 				Required:     true,
-				ForceNew:     true,
 				ValidateFunc: validation.StringLenBetween(1, 20),
 				Description:  "User's first name.",
 			},
 			"last_name": {
 				Type:         schema.TypeString,
 				Required:     true,
-				ForceNew:     true,
 				ValidateFunc: validation.StringLenBetween(1, 20),
 				Description:  "User's last name.",
 			},
 			"group_id": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				ForceNew:    true,
-				Description: "The UUID of a user's group.",
+				Computed:    true,
+				Description: "The UUID of a user's group. Users are added to the default group if not set.",
+			},
+			"role": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{client.UserRoleMember, client.UserRoleAdmin, client.UserRoleOwner}, false),
+				Description:  "The role of the user. Valid values are `MEMBER`, `ADMIN`, or `OWNER`. Users are created as `MEMBER` if not set, and a role given to them in the portal is kept.",
+			},
+			"status": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{client.UserStatusActive, client.UserStatusSuspended}, false),
+				// An invited user is PENDING until they sign in for the first
+				// time, and only then becomes ACTIVE.
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return old == client.UserStatusPending && new == client.UserStatusActive
+				},
+				Description: "The status of the user. Set it to `SUSPENDED` to prevent the user from connecting, or to `ACTIVE` to allow them again. Invited users are `PENDING` until they sign in for the first time.",
 			},
 			"devices": {
 				Type:        schema.TypeList,
 				Optional:    true,
-				ForceNew:    true,
 				MaxItems:    1,
-				Description: "When a user signs in, the device that they use will be added to their account. You can read more at [OpenVPN Cloud Device](https://openvpn.net/cloud-docs/device/).",
+				Description: "When a user signs in, the device that they use will be added to their account. You can read more at [OpenVPN Cloud Device](https://openvpn.net/cloud-docs/device/). Only the declared device is tracked: it is changed in place, and added again if it was deleted. Removing the block leaves the device on the user. Use `openvpncloud_device` to manage more than one device.",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the device.",
+						},
 						"name": {
 							Type:         schema.TypeString,
 							Required:     true,
@@ -71,16 +92,18 @@ func resourceUser() *schema.Resource {
 							Type:         schema.TypeString,
 							Required:     true,
 							ValidateFunc: validation.StringLenBetween(1, 120),
-							Description:  "A device description.",
+							Description:  "A device description. Changes made to it outside of Terraform are ignored.",
 						},
 						"ipv4_address": {
 							Type:        schema.TypeString,
 							Optional:    true,
+							Computed:    true,
 							Description: "An IPv4 address of the device.",
 						},
 						"ipv6_address": {
 							Type:        schema.TypeString,
 							Optional:    true,
+							Computed:    true,
 							Description: "An IPv6 address of the device.",
 						},
 					},
@@ -91,7 +114,7 @@ func resourceUser() *schema.Resource {
 }
 
 func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	username := d.Get("username").(string)
 	email := d.Get("email").(string)
@@ -121,23 +144,40 @@ func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m interface
 		GroupId:   groupId,
 		Devices:   devices,
 	}
-	user, err := c.CreateUser(u)
+	user, err := c.CreateUser(ctx, u)
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
 	d.SetId(user.Id)
-	return append(diags, diag.Diagnostic{
-		Severity: diag.Warning,
-		Summary:  "The user's role cannot be changed using the code.",
-		Detail:   "There is a bug in OpenVPN Cloud API that prevents setting the user's role during the creation. All users are created as Members by default. Once it's fixed, the provider will be updated accordingly.",
-	})
+	// The API creates every user as a pending member, whatever the request
+	// says, so the role and a suspension are applied with an update.
+	role := d.Get("role").(string)
+	suspended := d.Get("status").(string) == client.UserStatusSuspended
+	if (role != "" && user.Role != role) || suspended {
+		if role != "" {
+			user.Role = role
+		}
+		user.Status = ""
+		if suspended {
+			user.Status = client.UserStatusSuspended
+		}
+		err = c.UpdateUser(ctx, *user)
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+	}
+	return append(diags, resourceUserRead(ctx, d, m)...)
 }
 
 func resourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	userId := d.Id()
-	u, err := c.GetUserById(userId)
+	u, err := c.GetUserById(ctx, userId)
+	if client.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
 	if err != nil {
 		return append(diags, diag.FromErr(err)...)
 	}
@@ -149,18 +189,112 @@ func resourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}
 		d.Set("first_name", u.FirstName)
 		d.Set("last_name", u.LastName)
 		d.Set("group_id", u.GroupId)
-		d.Set("devices", u.Devices)
+		d.Set("role", u.Role)
+		d.Set("status", u.Status)
+		d.Set("devices", flattenUserDevices(d.Get("devices").([]interface{}), u.Devices))
 	}
 	return diags
 }
 
+func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	u := client.User{
+		Id:        d.Id(),
+		Email:     d.Get("email").(string),
+		FirstName: d.Get("first_name").(string),
+		LastName:  d.Get("last_name").(string),
+		GroupId:   d.Get("group_id").(string),
+		Role:      d.Get("role").(string),
+	}
+	// The status of a pending user cannot be sent back to the API.
+	if d.HasChange("status") {
+		u.Status = d.Get("status").(string)
+	}
+	err := c.UpdateUser(ctx, u)
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if d.HasChange("devices") {
+		err = updateUserDevice(ctx, c, d)
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+	}
+	return append(diags, resourceUserRead(ctx, d, m)...)
+}
+
+// updateUserDevice changes the declared device in place, or adds it again if
+// it was deleted. A device that is no longer declared is left on the user.
+func updateUserDevice(ctx context.Context, c client.OpenVPNCloudAPI, d *schema.ResourceData) error {
+	devices := d.Get("devices").([]interface{})
+	if len(devices) == 0 {
+		return nil
+	}
+	block := devices[0].(map[string]interface{})
+	device := client.Device{
+		Name:        block["name"].(string),
+		Description: block["description"].(string),
+		IPv4Address: block["ipv4_address"].(string),
+		IPv6Address: block["ipv6_address"].(string),
+	}
+	old, _ := d.GetChange("devices")
+	if known := old.([]interface{}); len(known) > 0 {
+		device.Id = known[0].(map[string]interface{})["id"].(string)
+	}
+	if device.Id == "" {
+		// The device was deleted, or the user imported, so it is looked up by
+		// name before it is added.
+		u, err := c.GetUserById(ctx, d.Id())
+		if err != nil {
+			return err
+		}
+		for _, existing := range u.Devices {
+			if existing.Name == device.Name {
+				device.Id = existing.Id
+				break
+			}
+		}
+	}
+	if device.Id == "" {
+		_, err := c.CreateDevice(ctx, d.Id(), device)
+		return err
+	}
+	return c.UpdateDevice(ctx, d.Id(), device)
+}
+
 func resourceUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(client.OpenVPNCloudAPI)
 	var diags diag.Diagnostics
 	userId := d.Id()
-	err := c.DeleteUser(userId)
-	if err != nil {
+	err := c.DeleteUser(ctx, userId)
+	if err != nil && !client.IsNotFound(err) {
 		return append(diags, diag.FromErr(err)...)
 	}
 	return diags
 }
+
+// flattenUserDevices returns the declared device as the user has it, matched
+// by its id, or by its name before the id is known. The other devices of the
+// user are left out, since they are added when the user signs in or managed
+// with openvpncloud_device. The declared description is kept, so that changes
+// made to it in the portal are ignored.
+func flattenUserDevices(declared []interface{}, devices []client.Device) []interface{} {
+	if len(declared) == 0 {
+		return nil
+	}
+	block := declared[0].(map[string]interface{})
+	id, _ := block["id"].(string)
+	for _, device := range devices {
+		if (id != "" && device.Id == id) || (id == "" && device.Name == block["name"].(string)) {
+			return []interface{}{map[string]interface{}{
+				"id":           device.Id,
+				"name":         device.Name,
+				"description":  block["description"],
+				"ipv4_address": device.IPv4Address,
+				"ipv6_address": device.IPv6Address,
+			}}
+		}
+	}
+	return nil
+}
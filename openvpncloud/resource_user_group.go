@@ -0,0 +1,160 @@
+package openvpncloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+)
+
+func resourceUserGroup() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Use `openvpncloud_user_group` to create an OpenVPN Cloud user group.",
+		CreateContext: resourceUserGroupCreate,
+		ReadContext:   resourceUserGroupRead,
+		UpdateContext: resourceUserGroupUpdate,
+		DeleteContext: resourceUserGroupDelete,
+		CustomizeDiff: resourceUserGroupCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringLenBetween(1, 40),
+				Description:  "The name of the user group.",
+			},
+			"vpn_region_ids": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotWhiteSpace,
+				},
+				Description: "The list of VPN region IDs the users of this group can connect to.",
+			},
+			"internet_access": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      client.UserGroupInternetAccessSplitTunnelOn,
+				ValidateFunc: validation.StringInSlice([]string{client.UserGroupInternetAccessSplitTunnelOn, client.UserGroupInternetAccessSplitTunnelOff, client.UserGroupInternetAccessRestrictedInternet}, false),
+				Description:  "The type of internet access provided. Valid values are `SPLIT_TUNNEL_ON`, `SPLIT_TUNNEL_OFF`, or `RESTRICTED_INTERNET`. Defaults to `SPLIT_TUNNEL_ON`.",
+			},
+			"max_device": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The maximum number of devices per user. Defaults to `3`.",
+			},
+			"system_subnets": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsCIDR,
+				},
+				Description: "The IPV4 and IPV6 subnets from which the devices of the users of this group get their addresses. Assigned by OpenVPN Cloud if not set.",
+			},
+		},
+	}
+}
+
+func resourceUserGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	ug := resourceDataToUserGroup(d)
+	userGroup, err := c.CreateUserGroup(ctx, ug)
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	d.SetId(userGroup.Id)
+	return append(diags, resourceUserGroupRead(ctx, d, m)...)
+}
+
+func resourceUserGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	userGroup, err := c.GetUserGroupById(ctx, d.Id())
+	if client.IsNotFound(err) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if userGroup == nil {
+		d.SetId("")
+		return diags
+	}
+	d.Set("name", userGroup.Name)
+	d.Set("vpn_region_ids", userGroup.VpnRegionIds)
+	d.Set("internet_access", userGroup.InternetAccess)
+	d.Set("max_device", userGroup.MaxDevice)
+	d.Set("system_subnets", userGroup.SystemSubnets)
+	return diags
+}
+
+func resourceUserGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	ug := resourceDataToUserGroup(d)
+	ug.Id = d.Id()
+	err := c.UpdateUserGroup(ctx, ug)
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	return append(diags, resourceUserGroupRead(ctx, d, m)...)
+}
+
+func resourceUserGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(client.OpenVPNCloudAPI)
+	var diags diag.Diagnostics
+	err := c.DeleteUserGroup(ctx, d.Id())
+	if err != nil && !client.IsNotFound(err) {
+		return append(diags, diag.FromErr(err)...)
+	}
+	return diags
+}
+
+func resourceDataToUserGroup(d *schema.ResourceData) client.UserGroup {
+	return client.UserGroup{
+		Name:           d.Get("name").(string),
+		VpnRegionIds:   getAddressesSlice(d.Get("vpn_region_ids").([]interface{})),
+		InternetAccess: d.Get("internet_access").(string),
+		MaxDevice:      d.Get("max_device").(int),
+		SystemSubnets:  getAddressesSlice(d.Get("system_subnets").([]interface{})),
+	}
+}
+
+// resourceUserGroupCustomizeDiff checks that every region exists, so that a
+// mistyped region is reported by its ID when planning rather than as a
+// rejected request when applying. Regions that are not known yet are left to
+// the API.
+func resourceUserGroupCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("vpn_region_ids") {
+		return nil
+	}
+	c := m.(client.OpenVPNCloudAPI)
+	return validateVpnRegionIds(ctx, c, knownListElements(d, "vpn_region_ids"))
+}
+
+// validateVpnRegionIds checks that every region exists.
+func validateVpnRegionIds(ctx context.Context, c client.OpenVPNCloudAPI, regionIds []string) error {
+	for _, regionId := range regionIds {
+		region, err := c.GetVpnRegion(ctx, regionId)
+		if err != nil {
+			return err
+		}
+		if region == nil {
+			return fmt.Errorf("vpn region %s does not exist", regionId)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package openvpncloud
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+)
+
+func TestAccOpenvpncloudUserGroup_basic(t *testing.T) {
+	resourceName := "openvpncloud_user_group.test"
+	name := acctest.RandomWithPrefix("tf-acc")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroy("openvpncloud_user_group", testAccUserGroupExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserGroupConfig(name, testAccVpnRegionId(), client.UserGroupInternetAccessSplitTunnelOn, 3),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccUserGroupExists),
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "vpn_region_ids.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "vpn_region_ids.0", testAccVpnRegionId()),
+					resource.TestCheckResourceAttr(resourceName, "internet_access", client.UserGroupInternetAccessSplitTunnelOn),
+					resource.TestCheckResourceAttr(resourceName, "max_device", "3"),
+					resource.TestCheckResourceAttrSet(resourceName, "system_subnets.0"),
+				),
+			},
+			{
+				Config: testAccUserGroupConfig(name+"-renamed", testAccVpnRegionId(), client.UserGroupInternetAccessRestrictedInternet, 5),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccUserGroupExists),
+					resource.TestCheckResourceAttr(resourceName, "name", name+"-renamed"),
+					resource.TestCheckResourceAttr(resourceName, "internet_access", client.UserGroupInternetAccessRestrictedInternet),
+					resource.TestCheckResourceAttr(resourceName, "max_device", "5"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:             testAccUserGroupConfig(name+"-renamed", testAccVpnRegionId(), client.UserGroupInternetAccessRestrictedInternet, 5),
+				Check:              testAccDeleteOutOfBand(resourceName, testAccUserGroupDelete),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccOpenvpncloudUserGroup_validation(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-acc")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccUserGroupConfig(name, testAccVpnRegionId(), "LOCAL", 3),
+				ExpectError: regexp.MustCompile(`expected internet_access to be one of`),
+			},
+			{
+				Config:      testAccUserGroupConfig(name, "tf-acc-missing-region", client.UserGroupInternetAccessSplitTunnelOn, 3),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`vpn region tf-acc-missing-region does not exist`),
+			},
+		},
+	})
+}
+
+func testAccUserGroupExists(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) (bool, error) {
+	userGroup, err := c.GetUserGroupById(context.Background(), rs.Primary.ID)
+	return testAccFound(userGroup != nil, err)
+}
+
+func testAccUserGroupDelete(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) error {
+	return c.DeleteUserGroup(context.Background(), rs.Primary.ID)
+}
+
+func testAccUserGroupConfig(name string, vpnRegionId string, internetAccess string, maxDevice int) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "openvpncloud_user_group" "test" {
+  name            = %q
+  vpn_region_ids  = [%q]
+  internet_access = %q
+  max_device      = %d
+}
+`, name, vpnRegionId, internetAccess, maxDevice)
+}
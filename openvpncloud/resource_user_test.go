@@ -0,0 +1,277 @@
+package openvpncloud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/patoarvizu/terraform-provider-openvpn-cloud/client"
+)
+
+func TestAccOpenvpncloudUser_basic(t *testing.T) {
+	resourceName := "openvpncloud_user.test"
+	username := acctest.RandomWithPrefix("tf-acc")
+	var userId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroy("openvpncloud_user", testAccUserExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig(username, "Jane", "", client.UserRoleMember, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccUserExists),
+					testAccCheckUserId(resourceName, &userId),
+					resource.TestCheckResourceAttr(resourceName, "username", username),
+					resource.TestCheckResourceAttr(resourceName, "email", username+"@example.com"),
+					resource.TestCheckResourceAttr(resourceName, "first_name", "Jane"),
+					resource.TestCheckResourceAttr(resourceName, "last_name", "Doe"),
+					resource.TestCheckResourceAttr(resourceName, "role", client.UserRoleMember),
+					resource.TestCheckResourceAttr(resourceName, "status", client.UserStatusPending),
+					resource.TestCheckResourceAttrSet(resourceName, "group_id"),
+				),
+			},
+			{
+				Config: testAccUserConfig(username, "Janet", "openvpncloud_user_group.test.id", client.UserRoleAdmin, client.UserStatusSuspended),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccUserExists),
+					testAccCheckUserId(resourceName, &userId),
+					resource.TestCheckResourceAttr(resourceName, "first_name", "Janet"),
+					resource.TestCheckResourceAttr(resourceName, "role", client.UserRoleAdmin),
+					resource.TestCheckResourceAttr(resourceName, "status", client.UserStatusSuspended),
+					resource.TestCheckResourceAttrPair(resourceName, "group_id", "openvpncloud_user_group.test", "id"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccUserConfig(username, "Janet", "openvpncloud_user_group.test.id", client.UserRoleAdmin, client.UserStatusSuspended),
+				Check: testAccAlterOutOfBand(resourceName, func(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) error {
+					return c.UpdateUser(context.Background(), client.User{
+						Id:        rs.Primary.ID,
+						Email:     rs.Primary.Attributes["email"],
+						FirstName: rs.Primary.Attributes["first_name"],
+						LastName:  rs.Primary.Attributes["last_name"],
+						Role:      client.UserRoleMember,
+					})
+				}),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config:             testAccUserConfig(username, "Janet", "openvpncloud_user_group.test.id", client.UserRoleAdmin, client.UserStatusSuspended),
+				Check:              testAccDeleteOutOfBand(resourceName, testAccUserDelete),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// TestAccOpenvpncloudUser_devices checks that the devices block only tracks
+// the device it declares, and not the ones managed with openvpncloud_device,
+// and that changes made to that device outside of Terraform never replace the
+// user.
+func TestAccOpenvpncloudUser_devices(t *testing.T) {
+	resourceName := "openvpncloud_user.test"
+	username := acctest.RandomWithPrefix("tf-acc")
+	var userId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroy("openvpncloud_user", testAccUserExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserDevicesConfig(username),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists(resourceName, testAccUserExists),
+					testAccCheckUserId(resourceName, &userId),
+					resource.TestCheckResourceAttr(resourceName, "devices.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "devices.0.name", "laptop"),
+					resource.TestCheckResourceAttrSet(resourceName, "devices.0.id"),
+					resource.TestCheckResourceAttrSet(resourceName, "devices.0.ipv4_address"),
+				),
+			},
+			{
+				Config: testAccUserDevicesConfig(username),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckUserId(resourceName, &userId),
+					resource.TestCheckResourceAttr(resourceName, "devices.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "devices.0.name", "laptop"),
+					testAccAlterOutOfBand(resourceName, testAccUserDeviceAlter(func(device *client.Device) {
+						device.Description = "Changed in the portal"
+					})),
+				),
+			},
+			{
+				Config: testAccUserDevicesConfig(username),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckUserId(resourceName, &userId),
+					resource.TestCheckResourceAttr(resourceName, "devices.0.description", "Jane's laptop"),
+					testAccAlterOutOfBand(resourceName, testAccUserDeviceAlter(func(device *client.Device) {
+						device.Name = "tablet"
+					})),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccUserDevicesConfig(username),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckUserId(resourceName, &userId),
+					resource.TestCheckResourceAttr(resourceName, "devices.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "devices.0.name", "laptop"),
+					testAccAlterOutOfBand(resourceName, func(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) error {
+						return c.DeleteDevice(context.Background(), rs.Primary.ID, rs.Primary.Attributes["devices.0.id"])
+					}),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccUserDevicesConfig(username),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckUserId(resourceName, &userId),
+					resource.TestCheckResourceAttr(resourceName, "devices.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "devices.0.name", "laptop"),
+					resource.TestCheckResourceAttr(resourceName, "devices.0.description", "Jane's laptop"),
+					testAccCheckExists("openvpncloud_device.phone", testAccDeviceExists),
+				),
+			},
+		},
+	})
+}
+
+// testAccUserDeviceAlter changes the device declared by a user directly
+// through the API.
+func testAccUserDeviceAlter(alter func(device *client.Device)) func(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) error {
+	return func(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) error {
+		ctx := context.Background()
+		device, err := c.GetDevice(ctx, rs.Primary.ID, rs.Primary.Attributes["devices.0.id"])
+		if err != nil {
+			return err
+		}
+		if device == nil {
+			return fmt.Errorf("device %s of user %s not found", rs.Primary.Attributes["devices.0.id"], rs.Primary.ID)
+		}
+		alter(device)
+		return c.UpdateDevice(ctx, rs.Primary.ID, *device)
+	}
+}
+
+// TestAccOpenvpncloudUser_unmanagedRole checks that a user whose role is not
+// set keeps the role given to it in the portal, and that its email is changed
+// in place.
+func TestAccOpenvpncloudUser_unmanagedRole(t *testing.T) {
+	resourceName := "openvpncloud_user.test"
+	username := acctest.RandomWithPrefix("tf-acc")
+	var userId string
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroy("openvpncloud_user", testAccUserExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig(username, "Jane", "", "", ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckUserId(resourceName, &userId),
+					resource.TestCheckResourceAttr(resourceName, "role", client.UserRoleMember),
+					testAccAlterOutOfBand(resourceName, func(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) error {
+						return c.UpdateUser(context.Background(), client.User{
+							Id:        rs.Primary.ID,
+							Email:     rs.Primary.Attributes["email"],
+							FirstName: rs.Primary.Attributes["first_name"],
+							LastName:  rs.Primary.Attributes["last_name"],
+							Role:      client.UserRoleAdmin,
+						})
+					}),
+				),
+			},
+			{
+				Config: strings.Replace(testAccUserConfig(username, "Jane", "", "", ""), "@example.com", "@example.org", 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckUserId(resourceName, &userId),
+					resource.TestCheckResourceAttr(resourceName, "email", username+"@example.org"),
+					resource.TestCheckResourceAttr(resourceName, "role", client.UserRoleAdmin),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckUserId checks that the user keeps the id it was created with,
+// which means that it was updated in place rather than replaced.
+func testAccCheckUserId(name string, userId *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", name)
+		}
+		if *userId == "" {
+			*userId = rs.Primary.ID
+		}
+		if rs.Primary.ID != *userId {
+			return fmt.Errorf("user %s was replaced by %s", *userId, rs.Primary.ID)
+		}
+		return nil
+	}
+}
+
+func testAccUserExists(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) (bool, error) {
+	user, err := c.GetUserById(context.Background(), rs.Primary.ID)
+	return testAccFound(user != nil, err)
+}
+
+func testAccUserDelete(c client.OpenVPNCloudAPI, rs *terraform.ResourceState) error {
+	return c.DeleteUser(context.Background(), rs.Primary.ID)
+}
+
+func testAccUserConfig(username string, firstName string, groupId string, role string, status string) string {
+	optional := ""
+	if groupId != "" {
+		optional += fmt.Sprintf("  group_id   = %s\n", groupId)
+	}
+	if role != "" {
+		optional += fmt.Sprintf("  role       = %q\n", role)
+	}
+	if status != "" {
+		optional += fmt.Sprintf("  status     = %q\n", status)
+	}
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "openvpncloud_user_group" "test" {
+  name           = "%[1]s-group"
+  vpn_region_ids = [%[4]q]
+}
+
+resource "openvpncloud_user" "test" {
+  username   = %[1]q
+  email      = "%[1]s@example.com"
+  first_name = %[2]q
+  last_name  = "Doe"
+%[3]s}
+`, username, firstName, optional, testAccVpnRegionId())
+}
+
+func testAccUserDevicesConfig(username string) string {
+	return testAccProviderConfig() + fmt.Sprintf(`
+resource "openvpncloud_user" "test" {
+  username   = %[1]q
+  email      = "%[1]s@example.com"
+  first_name = "Jane"
+  last_name  = "Doe"
+
+  devices {
+    name        = "laptop"
+    description = "Jane's laptop"
+  }
+}
+
+resource "openvpncloud_device" "phone" {
+  user_id = openvpncloud_user.test.id
+  name    = "phone"
+}
+`, username)
+}
@@ -0,0 +1,198 @@
+package openvpncloud
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TracesFileEnvVar names the environment variable holding the path of a
+	// file to which spans are appended as JSON, for runs that cannot reach an
+	// OTLP collector.
+	TracesFileEnvVar = "OPENVPN_CLOUD_TRACES_FILE"
+
+	tracerName = "github.com/patoarvizu/terraform-provider-openvpn-cloud/openvpncloud"
+)
+
+// tracingParent is the span of the calling pipeline, read from the TRACEPARENT
+// environment variable, under which the spans of the provider are recorded.
+var tracingParent trace.SpanContext
+
+// ConfigureTracing installs a global OpenTelemetry tracer provider when
+// tracing is enabled through the environment, and returns a function that
+// flushes the pending spans before the program exits.
+//
+// Spans are appended to the file named by OPENVPN_CLOUD_TRACES_FILE if it is
+// set. Otherwise they are exported over OTLP when OTEL_TRACES_EXPORTER is
+// `otlp` or an OTLP endpoint is set, using the protocol from
+// OTEL_EXPORTER_OTLP_PROTOCOL and the other standard OTEL_EXPORTER_OTLP_*
+// variables. OTEL_SDK_DISABLED=true or OTEL_TRACES_EXPORTER=none turn tracing
+// off.
+func ConfigureTracing(ctx context.Context, version string) (func(context.Context) error, error) {
+	shutdown := func(context.Context) error { return nil }
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") || os.Getenv("OTEL_TRACES_EXPORTER") == "none" {
+		return shutdown, nil
+	}
+	var processor sdktrace.SpanProcessor
+	if path := os.Getenv(TracesFileEnvVar); path != "" {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return shutdown, err
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return shutdown, err
+		}
+		// Spans are written as soon as they end, so that none is lost if the
+		// process is killed before it flushes them.
+		processor = sdktrace.NewSimpleSpanProcessor(exporter)
+		shutdown = func(context.Context) error { return f.Close() }
+	} else if otlpEnabled() {
+		exporter, err := newOTLPExporter(ctx)
+		if err != nil {
+			return shutdown, err
+		}
+		processor = sdktrace.NewBatchSpanProcessor(exporter)
+	} else {
+		return shutdown, nil
+	}
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String("terraform-provider-openvpncloud"),
+			semconv.ServiceVersionKey.String(version),
+		),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return shutdown, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	tracingParent = trace.SpanContextFromContext(propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{
+		"traceparent": os.Getenv("TRACEPARENT"),
+		"tracestate":  os.Getenv("TRACESTATE"),
+	}))
+	closeOutput := shutdown
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closeErr := closeOutput(ctx); err == nil {
+			err = closeErr
+		}
+		return err
+	}, nil
+}
+
+func otlpEnabled() bool {
+	return os.Getenv("OTEL_TRACES_EXPORTER") == "otlp" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+func newOTLPExporter(ctx context.Context) (*otlptrace.Exporter, error) {
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	switch protocol {
+	case "", "http/protobuf":
+		return otlptracehttp.New(ctx)
+	case "grpc":
+		return otlptracegrpc.New(ctx)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q: must be grpc or http/protobuf", protocol)
+	}
+}
+
+// traceResource wraps the CRUD functions of the resource or data source
+// typeName so that each call is recorded in its own span. mode is either
+// "managed" or "data", as in Terraform.
+func traceResource(typeName string, mode string, r *schema.Resource) {
+	if r.CreateContext != nil {
+		r.CreateContext = traceOperation(typeName, mode, "create", r.CreateContext)
+	}
+	if r.ReadContext != nil {
+		r.ReadContext = traceOperation(typeName, mode, "read", r.ReadContext)
+	}
+	if r.UpdateContext != nil {
+		r.UpdateContext = traceOperation(typeName, mode, "update", r.UpdateContext)
+	}
+	if r.DeleteContext != nil {
+		r.DeleteContext = traceOperation(typeName, mode, "delete", r.DeleteContext)
+	}
+}
+
+func traceOperation(typeName string, mode string, operation string, f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	name := typeName + " " + operation
+	if mode == "data" {
+		name = "data." + name
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		ctx, span := startSpan(ctx, name,
+			attribute.String("terraform.resource_type", typeName),
+			attribute.String("terraform.resource_mode", mode),
+			attribute.String("terraform.operation", operation),
+		)
+		defer span.End()
+		if d.Id() != "" {
+			span.SetAttributes(attribute.String("openvpncloud.object_id", d.Id()))
+		}
+		diags := f(ctx, d, m)
+		if d.Id() != "" {
+			span.SetAttributes(attribute.String("openvpncloud.object_id", d.Id()))
+		}
+		endSpan(span, diags)
+		return diags
+	}
+}
+
+// traceConfigure records the configuration of the provider, including the
+// token request, in its own span.
+func traceConfigure(f schema.ConfigureContextFunc) schema.ConfigureContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		ctx, span := startSpan(ctx, "provider configure")
+		defer span.End()
+		meta, diags := f(ctx, d)
+		endSpan(span, diags)
+		return meta, diags
+	}
+}
+
+// startSpan starts a span under the span in ctx, or under the span of the
+// calling pipeline if there is none.
+func startSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() && tracingParent.IsValid() {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, tracingParent)
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+func endSpan(span trace.Span, diags diag.Diagnostics) {
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			span.SetStatus(codes.Error, d.Summary)
+			return
+		}
+	}
+}
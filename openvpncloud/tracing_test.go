@@ -0,0 +1,57 @@
+package openvpncloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTraceResource(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			d.SetId("abc")
+			return nil
+		},
+		DeleteContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			return diag.Errorf("boom")
+		},
+	}
+	traceResource("openvpncloud_test", "managed", r)
+	d := r.TestResourceData()
+	r.CreateContext(context.Background(), d, nil)
+	r.DeleteContext(context.Background(), d, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name() != "openvpncloud_test create" {
+		t.Errorf("unexpected span name %q", spans[0].Name())
+	}
+	attributes := map[attribute.Key]string{}
+	for _, a := range spans[0].Attributes() {
+		attributes[a.Key] = a.Value.Emit()
+	}
+	if attributes["openvpncloud.object_id"] != "abc" || attributes["terraform.resource_type"] != "openvpncloud_test" {
+		t.Errorf("unexpected span attributes %v", attributes)
+	}
+	if spans[0].Status().Code == codes.Error {
+		t.Errorf("expected create span not to be an error")
+	}
+	if spans[1].Status().Code != codes.Error || spans[1].Status().Description != "boom" {
+		t.Errorf("unexpected delete span status %v", spans[1].Status())
+	}
+}